@@ -0,0 +1,342 @@
+package uinput
+
+import (
+	"fmt"
+	"sync"
+)
+
+// highest tracking ID assigned to a contact, before starting over at 0
+const maxTrackingID = 0xffff
+
+// tools reporting the number of fingers on the surface (one to five)
+var fingerTools = []uint16{
+	ButtonToolFinger,
+	ButtonToolDoubleTap,
+	ButtonToolTripleTap,
+	ButtonToolQuadTap,
+	ButtonToolQuintTap,
+}
+
+// A Contact is a single point of contact (e.g. a finger) of a multi touch device, following the multi touch protocol
+// (type B). Each contact is bound to a slot. Whenever it touches the surface, it is assigned a new tracking ID, which
+// identifies the contact until it is lifted again.
+type Contact struct {
+	contacts   *contactState
+	slot       int32
+	trackingID int32
+	x          int32
+	y          int32
+	pressure   int32
+	order      uint64
+}
+
+// Slot will return the slot the contact is bound to.
+func (c *Contact) Slot() int32 {
+	return c.slot
+}
+
+// TrackingID will return the tracking ID of the contact, or -1 if the contact does not touch the surface.
+func (c *Contact) TrackingID() int32 {
+	c.contacts.mutex.Lock()
+	defer c.contacts.mutex.Unlock()
+	return c.trackingID
+}
+
+// IsDown will return true if the contact touches the surface.
+func (c *Contact) IsDown() bool {
+	return c.TrackingID() >= 0
+}
+
+// Position will return the last position of the contact.
+func (c *Contact) Position() (x int32, y int32) {
+	c.contacts.mutex.Lock()
+	defer c.contacts.mutex.Unlock()
+	return c.x, c.y
+}
+
+// Pressure will return the last pressure of the contact (devices without pressure support always report 0).
+func (c *Contact) Pressure() int32 {
+	c.contacts.mutex.Lock()
+	defer c.contacts.mutex.Unlock()
+	return c.pressure
+}
+
+// Down will put the contact onto the surface at the given position. This fails if the contact is already down.
+// On devices that support pressure, the default pressure of the device is used.
+func (c *Contact) Down(x int32, y int32) error {
+	return c.contacts.apply(contactUpdate{contact: c, action: contactDown, x: x, y: y})
+}
+
+// DownWithPressure will put the contact onto the surface at the given position, using the given pressure. The
+// pressure is ignored by devices that do not support it.
+func (c *Contact) DownWithPressure(x int32, y int32, pressure int32) error {
+	return c.contacts.apply(contactUpdate{contact: c, action: contactDown, x: x, y: y, pressure: &pressure})
+}
+
+// Move will move a contact that is down to the given position. Only the coordinates that actually changed are sent.
+func (c *Contact) Move(x int32, y int32) error {
+	return c.contacts.apply(contactUpdate{contact: c, action: contactMove, x: x, y: y})
+}
+
+// MoveWithPressure will move a contact that is down to the given position and change its pressure.
+func (c *Contact) MoveWithPressure(x int32, y int32, pressure int32) error {
+	return c.contacts.apply(contactUpdate{contact: c, action: contactMove, x: x, y: y, pressure: &pressure})
+}
+
+// Up will lift the contact off the surface. This fails if the contact is not down.
+func (c *Contact) Up() error {
+	return c.contacts.apply(contactUpdate{contact: c, action: contactUp})
+}
+
+// TouchDownAt will put the contact onto the surface at the given position, or move it there if it is already down.
+func (c *Contact) TouchDownAt(x int32, y int32) error {
+	if c.IsDown() {
+		return c.Move(x, y)
+	}
+	return c.Down(x, y)
+}
+
+// TouchUp will lift the contact off the surface.
+func (c *Contact) TouchUp() error {
+	return c.Up()
+}
+
+type contactAction int
+
+const (
+	contactDown contactAction = iota
+	contactMove
+	contactUp
+)
+
+type contactUpdate struct {
+	contact  *Contact
+	action   contactAction
+	x        int32
+	y        int32
+	pressure *int32
+}
+
+// contactState holds the contacts of a multi touch device, along with the state of the device that is shared by all
+// contacts (the currently selected slot and the next tracking ID).
+type contactState struct {
+	mutex          sync.Mutex
+	deviceFile     uinputFile
+	contacts       []*Contact
+	slot           int32
+	nextTrackingID int32
+	nextOrder      uint64
+
+	// devices supporting pressure send ABS_MT_PRESSURE, using the default pressure unless given explicitly
+	pressure        bool
+	defaultPressure int32
+
+	// devices emulating a single touch pointer send ABS_X, ABS_Y (and ABS_PRESSURE) of the oldest contact
+	emulatePointer bool
+	pointer        pointerState
+}
+
+type pointerState struct {
+	valid    bool
+	x        int32
+	y        int32
+	pressure int32
+}
+
+func newContactState(deviceFile uinputFile, maxContacts int32) *contactState {
+	s := &contactState{deviceFile: deviceFile, slot: 0}
+	for i := int32(0); i < maxContacts; i++ {
+		s.contacts = append(s.contacts, &Contact{contacts: s, slot: i, trackingID: -1})
+	}
+	return s
+}
+
+func (s *contactState) all() []*Contact {
+	return append([]*Contact(nil), s.contacts...)
+}
+
+// fingers will return the number of contacts that are down. The caller needs to hold the mutex.
+func (s *contactState) fingers() int {
+	count := 0
+	for _, c := range s.contacts {
+		if c.trackingID >= 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// apply will send all given updates as a single frame, along with the changes of BTN_TOUCH and BTN_TOOL_* that
+// result from the changed number of contacts on the surface. Either all updates are applied or none.
+func (s *contactState) apply(updates ...contactUpdate) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	results := make(map[*Contact]Contact)
+	var order []*Contact
+	frame := newFrame(s.deviceFile)
+	slot := s.slot
+	nextTrackingID := s.nextTrackingID
+	nextOrder := s.nextOrder
+	fingersBefore := s.fingers()
+	fingersAfter := fingersBefore
+
+	for _, u := range updates {
+		c := u.contact
+		if c.contacts != s {
+			return fmt.Errorf("contact in slot %d does not belong to this device", c.slot)
+		}
+		if _, ok := results[c]; ok {
+			return fmt.Errorf("contact in slot %d must not be updated twice within a single frame", c.slot)
+		}
+
+		result := *c
+		switch u.action {
+		case contactDown:
+			if c.trackingID >= 0 {
+				return fmt.Errorf("contact in slot %d is already down", c.slot)
+			}
+			result.trackingID, result.x, result.y = nextTrackingID, u.x, u.y
+			result.pressure, result.order = s.defaultPressure, nextOrder
+			nextTrackingID = (nextTrackingID + 1) % (maxTrackingID + 1)
+			nextOrder++
+			fingersAfter++
+		case contactMove:
+			if c.trackingID < 0 {
+				return fmt.Errorf("contact in slot %d is not down", c.slot)
+			}
+			result.x, result.y = u.x, u.y
+		case contactUp:
+			if c.trackingID < 0 {
+				return fmt.Errorf("contact in slot %d is not down", c.slot)
+			}
+			result.trackingID = -1
+			fingersAfter--
+		}
+		if !s.pressure {
+			result.pressure = 0
+		} else if u.pressure != nil && u.action != contactUp {
+			result.pressure = *u.pressure
+		}
+		results[c] = result
+		order = append(order, c)
+
+		if u.action == contactMove && result.x == c.x && result.y == c.y && result.pressure == c.pressure {
+			continue
+		}
+		if slot != c.slot {
+			frame.Emit(evAbs, absMtSlot, c.slot)
+			slot = c.slot
+		}
+		if u.action != contactMove {
+			frame.Emit(evAbs, absMtTrackingId, result.trackingID)
+		}
+		if u.action == contactUp {
+			continue
+		}
+		if u.action == contactDown || result.x != c.x {
+			frame.Emit(evAbs, absMtPositionX, result.x)
+		}
+		if u.action == contactDown || result.y != c.y {
+			frame.Emit(evAbs, absMtPositionY, result.y)
+		}
+		if s.pressure && (u.action == contactDown || result.pressure != c.pressure) {
+			frame.Emit(evAbs, absMtPressure, result.pressure)
+		}
+	}
+
+	if (fingersBefore == 0) != (fingersAfter == 0) {
+		frame.Emit(evKey, evBtnTouch, boolToValue(fingersAfter > 0))
+	}
+	toolBefore, toolAfter := fingerTool(fingersBefore), fingerTool(fingersAfter)
+	if toolBefore != toolAfter {
+		if toolBefore != 0 {
+			frame.Emit(evKey, toolBefore, btnStateReleased)
+		}
+		if toolAfter != 0 {
+			frame.Emit(evKey, toolAfter, btnStatePressed)
+		}
+	}
+
+	pointer := s.pointer
+	if s.emulatePointer {
+		pointer = s.emulatedPointer(results, frame)
+	}
+
+	err := frame.Flush()
+	if err != nil {
+		// the slot selected by the device is unknown now, so make sure to select it explicitly next time
+		s.slot = -1
+		return fmt.Errorf("failed to send contact update: %v", err)
+	}
+
+	s.slot = slot
+	s.nextTrackingID = nextTrackingID
+	s.nextOrder = nextOrder
+	s.pointer = pointer
+	for _, c := range order {
+		result := results[c]
+		c.trackingID, c.x, c.y, c.pressure, c.order = result.trackingID, result.x, result.y, result.pressure, result.order
+	}
+	return nil
+}
+
+// emulatedPointer will add the single touch events of the oldest contact that is down (after applying the given
+// results) to the frame, as long as they changed. The caller needs to hold the mutex.
+func (s *contactState) emulatedPointer(results map[*Contact]Contact, frame *Frame) pointerState {
+	var oldest *Contact
+	for _, c := range s.contacts {
+		state := *c
+		if result, ok := results[c]; ok {
+			state = result
+		}
+		if state.trackingID >= 0 && (oldest == nil || state.order < oldest.order) {
+			oldest = &state
+		}
+	}
+
+	pointer := s.pointer
+	if oldest == nil {
+		if s.pressure && pointer.pressure != 0 {
+			frame.Emit(evAbs, absPressure, 0)
+		}
+		pointer.pressure = 0
+		return pointer
+	}
+
+	if !pointer.valid || oldest.x != pointer.x {
+		frame.Emit(evAbs, absX, oldest.x)
+	}
+	if !pointer.valid || oldest.y != pointer.y {
+		frame.Emit(evAbs, absY, oldest.y)
+	}
+	if s.pressure && (!pointer.valid || oldest.pressure != pointer.pressure) {
+		frame.Emit(evAbs, absPressure, oldest.pressure)
+	}
+	return pointerState{valid: true, x: oldest.x, y: oldest.y, pressure: oldest.pressure}
+}
+
+// releaseAll will lift all contacts that are down within a single frame.
+func (s *contactState) releaseAll() error {
+	var updates []contactUpdate
+	for _, c := range s.all() {
+		if c.IsDown() {
+			updates = append(updates, contactUpdate{contact: c, action: contactUp})
+		}
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+	return s.apply(updates...)
+}
+
+// fingerTool will return the BTN_TOOL_* code reporting the given number of fingers, or 0 if there are no fingers.
+func fingerTool(fingers int) uint16 {
+	if fingers <= 0 {
+		return 0
+	}
+	if fingers > len(fingerTools) {
+		fingers = len(fingerTools)
+	}
+	return fingerTools[fingers-1]
+}
@@ -0,0 +1,279 @@
+package uinput
+
+import (
+	"fmt"
+	"io"
+)
+
+// InputID identifies a device by its bus type (see the Bus* constants in keycodes.go), vendor, product and version.
+type InputID struct {
+	Bustype uint16
+	Vendor  uint16
+	Product uint16
+	Version uint16
+}
+
+// DeviceSpec declares the capabilities of a generic device. Event types are registered implicitly for every non-empty
+// list of codes, additional event types (like EvRep) may be added using EventTypes.
+type DeviceSpec struct {
+	ID         InputID
+	EventTypes []uint16
+	Keys       []uint16
+	RelAxes    []uint16
+	AbsAxes    []AxisConfig
+	MscEvents  []uint16
+	LEDs       []uint16
+	Switches   []uint16
+	Sounds     []uint16
+	Properties []uint16
+}
+
+// A Device is a generic input device with an arbitrary set of capabilities, as declared by its DeviceSpec.
+// Events may only be sent for codes that are part of the spec.
+type Device interface {
+	// KeyPress will cause the key to be pressed and immediately released.
+	KeyPress(key int) error
+
+	// KeyDown will send a key-press event to the device. Note that the key will be "held down" until KeyUp is called.
+	KeyDown(key int) error
+
+	// KeyUp will send a key-release event to the device.
+	KeyUp(key int) error
+
+	// SendRelEvent will send a relative axis event with the given delta.
+	SendRelEvent(code uint16, delta int32) error
+
+	// SendAbsEvent will send an absolute axis event with the given value.
+	SendAbsEvent(code uint16, value int32) error
+
+	// SendMscEvent will send a miscellaneous event with the given value.
+	SendMscEvent(code uint16, value int32) error
+
+	// SendLedEvent will switch the given led on or off.
+	SendLedEvent(code uint16, on bool) error
+
+	// SendSwitchEvent will toggle the given switch.
+	SendSwitchEvent(code uint16, on bool) error
+
+	// LEDs will return the current state of the leds declared by the spec, as set by the system.
+	LEDs() LEDState
+
+	// FetchSyspath will return the syspath to the device file.
+	FetchSyspath() (string, error)
+
+	EventEmitter
+
+	EventReceiver
+
+	Releaser
+
+	io.Closer
+}
+
+type vDevice struct {
+	name         []byte
+	deviceFile   uinputFile
+	capabilities map[uint16]map[uint16]bool
+	reader       *eventReader
+}
+
+// CreateDevice will create a new device with the capabilities declared by the given spec.
+func CreateDevice(path string, name []byte, spec DeviceSpec, options ...DeviceOption) (Device, error) {
+	err := validateDevicePath(path)
+	if err != nil {
+		return nil, err
+	}
+	err = validateUinputName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	fd, err := createDevice(path, name, spec, newDeviceOptions(options))
+	if err != nil {
+		return nil, err
+	}
+
+	return vDevice{name: name, deviceFile: fd, capabilities: spec.capabilities(), reader: startEventReader(fd, nil)}, nil
+}
+
+func (vd vDevice) KeyPress(key int) error {
+	err := vd.KeyDown(key)
+	if err != nil {
+		return err
+	}
+	return vd.KeyUp(key)
+}
+
+func (vd vDevice) KeyDown(key int) error {
+	if !vd.supports(evKey, uint16(key)) {
+		return fmt.Errorf("failed to perform KeyDown. Code %d is not registered", key)
+	}
+	return sendBtnEvent(vd.deviceFile, []int{key}, btnStatePressed)
+}
+
+func (vd vDevice) KeyUp(key int) error {
+	if !vd.supports(evKey, uint16(key)) {
+		return fmt.Errorf("failed to perform KeyUp. Code %d is not registered", key)
+	}
+	return sendBtnEvent(vd.deviceFile, []int{key}, btnStateReleased)
+}
+
+func (vd vDevice) SendRelEvent(code uint16, delta int32) error {
+	return vd.sendEvent(evRel, code, delta)
+}
+
+func (vd vDevice) SendAbsEvent(code uint16, value int32) error {
+	return vd.sendEvent(evAbs, code, value)
+}
+
+func (vd vDevice) SendMscEvent(code uint16, value int32) error {
+	return vd.sendEvent(evMsc, code, value)
+}
+
+func (vd vDevice) SendLedEvent(code uint16, on bool) error {
+	return vd.sendEvent(evLed, code, boolToValue(on))
+}
+
+func (vd vDevice) SendSwitchEvent(code uint16, on bool) error {
+	return vd.sendEvent(evSw, code, boolToValue(on))
+}
+
+func (vd vDevice) LEDs() LEDState {
+	return vd.reader.ledState()
+}
+
+func (vd vDevice) FetchSyspath() (string, error) {
+	return fetchSyspath(vd.deviceFile)
+}
+
+// Emit will send a single raw event to the device, immediately followed by a SYN_REPORT.
+func (vd vDevice) Emit(evType uint16, code uint16, value int32) error {
+	return emitEvent(vd.deviceFile, evType, code, value)
+}
+
+// NewFrame will create an empty frame that may be used to send multiple events at once.
+func (vd vDevice) NewFrame() *Frame {
+	return newFrame(vd.deviceFile)
+}
+
+// SetEventHandler registers a handler that is invoked for every event sent to the device.
+func (vd vDevice) SetEventHandler(handler EventHandler) {
+	vd.reader.setHandler(handler)
+}
+
+// ReleaseAll will release all keys, buttons and touch contacts that are currently held down.
+func (vd vDevice) ReleaseAll() error {
+	return releaseAll(vd.deviceFile)
+}
+
+func (vd vDevice) Close() error {
+	return closeDevice(vd.deviceFile)
+}
+
+func (vd vDevice) supports(evType uint16, code uint16) bool {
+	return vd.capabilities[evType][code]
+}
+
+func (vd vDevice) sendEvent(evType uint16, code uint16, value int32) error {
+	if !vd.supports(evType, code) {
+		return fmt.Errorf("code %d of event type %d is not registered", code, evType)
+	}
+	return emitEvent(vd.deviceFile, evType, code, value)
+}
+
+func boolToValue(on bool) int32 {
+	if on {
+		return 1
+	}
+	return 0
+}
+
+// capabilityList groups the codes of a single event type along with the ioctl used to register them.
+type capabilityList struct {
+	evType uint16
+	setBit uintptr
+	codes  []uint16
+	name   string
+}
+
+func (spec DeviceSpec) capabilityLists() []capabilityList {
+	var absCodes []uint16
+	for _, axis := range spec.AbsAxes {
+		absCodes = append(absCodes, axis.Code)
+	}
+
+	return []capabilityList{
+		{evType: evKey, setBit: uiSetKeyBit, codes: spec.Keys, name: "key"},
+		{evType: evRel, setBit: uiSetRelBit, codes: spec.RelAxes, name: "relative axis"},
+		{evType: evAbs, setBit: uiSetAbsBit, codes: absCodes, name: "absolute axis"},
+		{evType: evMsc, setBit: uiSetMscBit, codes: spec.MscEvents, name: "misc"},
+		{evType: evLed, setBit: uiSetLedBit, codes: spec.LEDs, name: "led"},
+		{evType: evSw, setBit: uiSetSwBit, codes: spec.Switches, name: "switch"},
+		{evType: evSnd, setBit: uiSetSndBit, codes: spec.Sounds, name: "sound"},
+	}
+}
+
+func (spec DeviceSpec) capabilities() map[uint16]map[uint16]bool {
+	capabilities := make(map[uint16]map[uint16]bool)
+	for _, list := range spec.capabilityLists() {
+		codes := make(map[uint16]bool, len(list.codes))
+		for _, code := range list.codes {
+			codes[code] = true
+		}
+		capabilities[list.evType] = codes
+	}
+	return capabilities
+}
+
+func createDevice(path string, name []byte, spec DeviceSpec, options deviceOptions) (fd uinputFile, err error) {
+	deviceFile, err := createDeviceFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not create input device: %v", err)
+	}
+
+	err = registerCapabilities(deviceFile, spec)
+	if err != nil {
+		_ = deviceFile.Close()
+		return nil, err
+	}
+
+	return createUsbDevice(deviceFile,
+		uinputSetup{
+			Name: toUinputName(name),
+			ID: inputID{
+				Bustype: spec.ID.Bustype,
+				Vendor:  spec.ID.Vendor,
+				Product: spec.ID.Product,
+				Version: spec.ID.Version}},
+		toAbsSetups(spec.AbsAxes),
+		options)
+}
+
+func registerCapabilities(deviceFile uinputFile, spec DeviceSpec) error {
+	for _, evType := range spec.EventTypes {
+		err := registerDevice(deviceFile, uintptr(evType))
+		if err != nil {
+			return fmt.Errorf("failed to register event type %d: %v", evType, err)
+		}
+	}
+
+	for _, list := range spec.capabilityLists() {
+		if len(list.codes) == 0 {
+			continue
+		}
+
+		err := registerDevice(deviceFile, uintptr(list.evType))
+		if err != nil {
+			return fmt.Errorf("failed to register %s events: %v", list.name, err)
+		}
+
+		for _, code := range list.codes {
+			err = ioctl(deviceFile, list.setBit, uintptr(code))
+			if err != nil {
+				return fmt.Errorf("failed to register %s code %d: %v", list.name, code, err)
+			}
+		}
+	}
+
+	return registerProperties(deviceFile, spec.Properties)
+}
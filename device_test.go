@@ -0,0 +1,143 @@
+package uinput
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+var footPedalSpec = DeviceSpec{
+	ID:       InputID{Bustype: BusUsb, Vendor: 0x4711, Product: 0x0818, Version: 1},
+	Keys:     []uint16{KeyLeftctrl, KeyLeftshift, KeyF13},
+	RelAxes:  []uint16{RelWheel},
+	AbsAxes:  []AxisConfig{{Code: AbsGas, Min: 0, Max: 1023, Fuzz: 4}},
+	Switches: []uint16{SwDock},
+}
+
+func TestDeviceEvents(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	dev, err := CreateDevice(fake.Path(), []byte("Test Foot Pedal"), footPedalSpec)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual device. Last error was: %s\n", err)
+	}
+	defer func(dev Device) {
+		err := dev.Close()
+		if err != nil {
+			t.Fatalf("Failed to close device. Last error was: %s\n", err)
+		}
+	}(dev)
+
+	err = dev.KeyPress(KeyF13)
+	if err != nil {
+		t.Fatalf("Failed to send key press. Last error was: %s\n", err)
+	}
+
+	err = dev.SendRelEvent(RelWheel, -1)
+	if err != nil {
+		t.Fatalf("Failed to send relative axis event. Last error was: %s\n", err)
+	}
+
+	err = dev.SendAbsEvent(AbsGas, 512)
+	if err != nil {
+		t.Fatalf("Failed to send absolute axis event. Last error was: %s\n", err)
+	}
+
+	err = dev.SendSwitchEvent(SwDock, true)
+	if err != nil {
+		t.Fatalf("Failed to send switch event. Last error was: %s\n", err)
+	}
+
+	fake.Device("Test Foot Pedal").ExpectEvents(t,
+		Event{Type: EvKey, Code: KeyF13, Value: 1},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvKey, Code: KeyF13, Value: 0},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvRel, Code: RelWheel, Value: -1},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvAbs, Code: AbsGas, Value: 512},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvSw, Code: SwDock, Value: 1},
+		Event{Type: EvSyn, Code: SynReport})
+}
+
+func TestDeviceRejectsUnregisteredCodes(t *testing.T) {
+	dev := vDevice{capabilities: footPedalSpec.capabilities()}
+
+	err := dev.KeyDown(KeyA)
+	if err == nil {
+		t.Fatalf("Expected error due to unregistered key, but no error was returned.")
+	}
+	err = dev.SendAbsEvent(AbsX, 1)
+	if err == nil {
+		t.Fatalf("Expected error due to unregistered axis, but no error was returned.")
+	}
+	err = dev.SendLedEvent(LedCapsLock, true)
+	if err == nil {
+		t.Fatalf("Expected error due to unregistered led, but no error was returned.")
+	}
+}
+
+func TestDeviceFailsOnClosedDevice(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	dev, err := CreateDevice(fake.Path(), []byte("Test Foot Pedal"), footPedalSpec)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual device. Last error was: %s\n", err)
+	}
+	_ = dev.Close()
+
+	err = dev.KeyPress(KeyF13)
+	if err == nil {
+		t.Fatalf("Expected error due to closed device, but no error was returned.")
+	}
+	err = dev.SendRelEvent(RelWheel, 1)
+	if err == nil {
+		t.Fatalf("Expected error due to closed device, but no error was returned.")
+	}
+}
+
+func TestDeviceCreationFailsOnEmptyPath(t *testing.T) {
+	expected := "device path must not be empty"
+	_, err := CreateDevice("", []byte("Device"), footPedalSpec)
+	if err.Error() != expected {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+}
+
+func TestDeviceCreationFailsOnNonExistentPathName(t *testing.T) {
+	path := "/some/bogus/path"
+	_, err := CreateDevice(path, []byte("Device"), footPedalSpec)
+	if !os.IsNotExist(err) {
+		t.Fatalf("Expected: os.IsNotExist error\nActual: %s", err)
+	}
+}
+
+func TestDeviceCreationFailsOnWrongPathName(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-device-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer file.Close()
+
+	expected := "failed to register key events: failed to close device: inappropriate ioctl for device"
+	_, err = CreateDevice(file.Name(), []byte("Device"), footPedalSpec)
+	if err == nil || !(expected == err.Error()) {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+}
+
+func TestDeviceCreationFailsIfNameIsTooLong(t *testing.T) {
+	name := "adsfdsferqewoirueworiuejdsfjdfa;ljoewrjeworiewuoruew;rj;kdlfjoeai;jfewoaifjef;das"
+	expected := fmt.Sprintf("device name %s is too long (maximum of %d characters allowed)", name, uinputMaxNameSize)
+	fake := NewFake()
+	defer fake.Close()
+
+	_, err := CreateDevice(fake.Path(), []byte(name), footPedalSpec)
+	if err.Error() != expected {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+}
@@ -3,7 +3,6 @@ package uinput
 import (
 	"fmt"
 	"io"
-	"os"
 	"syscall"
 )
 
@@ -13,16 +12,23 @@ type Dial interface {
 	// Turn will simulate a dial movement.
 	Turn(delta int32) error
 
+	EventEmitter
+
+	EventReceiver
+
+	Releaser
+
 	io.Closer
 }
 
 type vDial struct {
 	name       []byte
-	deviceFile *os.File
+	deviceFile uinputFile
+	reader     *eventReader
 }
 
 // CreateDial will create a new dial input device. A dial is a device that can trigger rotation events.
-func CreateDial(path string, name []byte) (Dial, error) {
+func CreateDial(path string, name []byte, options ...DeviceOption) (Dial, error) {
 	err := validateDevicePath(path)
 	if err != nil {
 		return nil, err
@@ -32,12 +38,12 @@ func CreateDial(path string, name []byte) (Dial, error) {
 		return nil, err
 	}
 
-	fd, err := createDial(path, name)
+	fd, err := createDial(path, name, newDeviceOptions(options))
 	if err != nil {
 		return nil, err
 	}
 
-	return vDial{name: name, deviceFile: fd}, nil
+	return vDial{name: name, deviceFile: fd, reader: startEventReader(fd, nil)}, nil
 }
 
 // Turn will simulate a dial movement.
@@ -45,12 +51,32 @@ func (vRel vDial) Turn(delta int32) error {
 	return sendDialEvent(vRel.deviceFile, delta)
 }
 
+// Emit will send a single raw event to the device, immediately followed by a SYN_REPORT.
+func (vRel vDial) Emit(evType uint16, code uint16, value int32) error {
+	return emitEvent(vRel.deviceFile, evType, code, value)
+}
+
+// NewFrame will create an empty frame that may be used to send multiple events at once.
+func (vRel vDial) NewFrame() *Frame {
+	return newFrame(vRel.deviceFile)
+}
+
+// SetEventHandler registers a handler that is invoked for every event sent to the device.
+func (vRel vDial) SetEventHandler(handler EventHandler) {
+	vRel.reader.setHandler(handler)
+}
+
+// ReleaseAll will release all keys, buttons and touch contacts that are currently held down.
+func (vRel vDial) ReleaseAll() error {
+	return releaseAll(vRel.deviceFile)
+}
+
 // Close closes the device and releases the device.
 func (vRel vDial) Close() error {
 	return closeDevice(vRel.deviceFile)
 }
 
-func createDial(path string, name []byte) (fd *os.File, err error) {
+func createDial(path string, name []byte, options deviceOptions) (fd uinputFile, err error) {
 	deviceFile, err := createDeviceFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("could not create dial input device: %v", err)
@@ -70,16 +96,18 @@ func createDial(path string, name []byte) (fd *os.File, err error) {
 	}
 
 	return createUsbDevice(deviceFile,
-		uinputUserDev{
+		uinputSetup{
 			Name: toUinputName(name),
 			ID: inputID{
 				Bustype: busUsb,
 				Vendor:  0x4711,
 				Product: 0x0816,
-				Version: 1}})
+				Version: 1}},
+		nil,
+		options)
 }
 
-func sendDialEvent(deviceFile *os.File, delta int32) error {
+func sendDialEvent(deviceFile uinputFile, delta int32) error {
 	iev := inputEvent{
 		Time:  syscall.Timeval{Sec: 0, Usec: 0},
 		Type:  evRel,
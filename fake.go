@@ -0,0 +1,572 @@
+package uinput
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// time UploadEffect and EraseEffect wait for the device to complete a request (the kernel waits 30 seconds)
+const fakeFFTimeout = 5 * time.Second
+
+// A Fake is an in-memory replacement for the uinput device node, which allows to test code built on top of this
+// package without access to /dev/uinput. Devices are created as usual, but using the path returned by Path().
+// Instead of being registered with the kernel, the capabilities, axis configuration and identity of each device,
+// as well as all events that are sent to it, are recorded and may be inspected using the related FakeDevice.
+type Fake struct {
+	path    string
+	mutex   sync.Mutex
+	devices []*FakeDevice
+}
+
+// FakeDevice holds the state of a single device that was created using a Fake.
+type FakeDevice struct {
+	mutex     sync.Mutex
+	cond      *sync.Cond
+	sysname   string
+	setup     uinputSetup
+	setupDone bool
+	phys      string
+	created   bool
+	destroyed bool
+	closed    bool
+	evBits    map[uint16]bool
+	codes     map[uint16]map[uint16]bool
+	props     map[uint16]bool
+	absinfo   map[uint16]absInfo
+	events    []Event
+	pending   []inputEvent
+	ffID      uint32
+	ffPending map[uint32]*fakeFFRequest
+}
+
+// fakeFFRequest is a force feedback upload or erase that waits for the device to complete it.
+type fakeFFRequest struct {
+	upload *uinputFfUpload
+	erase  *uinputFfErase
+	done   chan struct{}
+}
+
+// TestingT is the subset of testing.TB that is used by the assertion helpers of FakeDevice.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+var fakeRegistry = struct {
+	mutex  sync.Mutex
+	fakes  map[string]*Fake
+	nextID int
+}{fakes: make(map[string]*Fake)}
+
+// NewFake will create a new fake uinput device node. Call Close once the fake is no longer needed.
+func NewFake() *Fake {
+	fakeRegistry.mutex.Lock()
+	defer fakeRegistry.mutex.Unlock()
+
+	fakeRegistry.nextID++
+	fake := &Fake{path: fmt.Sprintf("fake://uinput/%d", fakeRegistry.nextID)}
+	fakeRegistry.fakes[fake.path] = fake
+	return fake
+}
+
+func lookupFake(path string) *Fake {
+	fakeRegistry.mutex.Lock()
+	defer fakeRegistry.mutex.Unlock()
+	return fakeRegistry.fakes[path]
+}
+
+// Path will return the path that needs to be passed to the Create* functions in order to create fake devices.
+func (f *Fake) Path() string {
+	return f.path
+}
+
+// Close will unregister the fake. Devices that were already created remain accessible.
+func (f *Fake) Close() error {
+	fakeRegistry.mutex.Lock()
+	defer fakeRegistry.mutex.Unlock()
+	delete(fakeRegistry.fakes, f.path)
+	return nil
+}
+
+// Devices will return all devices that were created using this fake, in order of creation.
+func (f *Fake) Devices() []*FakeDevice {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]*FakeDevice(nil), f.devices...)
+}
+
+// Device will return the most recently created device with the given name, or nil if there is no such device.
+func (f *Fake) Device(name string) *FakeDevice {
+	devices := f.Devices()
+	for i := len(devices) - 1; i >= 0; i-- {
+		if devices[i].Name() == name {
+			return devices[i]
+		}
+	}
+	return nil
+}
+
+func (f *Fake) open() *fakeFile {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	dev := &FakeDevice{
+		sysname:   fmt.Sprintf("input%d", len(f.devices)),
+		evBits:    make(map[uint16]bool),
+		codes:     make(map[uint16]map[uint16]bool),
+		props:     make(map[uint16]bool),
+		absinfo:   make(map[uint16]absInfo),
+		ffPending: make(map[uint32]*fakeFFRequest),
+	}
+	dev.cond = sync.NewCond(&dev.mutex)
+	f.devices = append(f.devices, dev)
+	return &fakeFile{dev: dev}
+}
+
+// Name will return the name the device was set up with.
+func (d *FakeDevice) Name() string {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return string(bytes.TrimRight(d.setup.Name[:], "\x00"))
+}
+
+// Phys will return the physical path the device was set up with.
+func (d *FakeDevice) Phys() string {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.phys
+}
+
+// ID will return the identity the device was set up with.
+func (d *FakeDevice) ID() InputID {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return InputID{
+		Bustype: d.setup.ID.Bustype,
+		Vendor:  d.setup.ID.Vendor,
+		Product: d.setup.ID.Product,
+		Version: d.setup.ID.Version,
+	}
+}
+
+// EffectsMax will return the number of force feedback effects the device supports.
+func (d *FakeDevice) EffectsMax() uint32 {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.setup.EffectsMax
+}
+
+// IsCreated will return true if the device has been created and not yet been destroyed.
+func (d *FakeDevice) IsCreated() bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.created && !d.destroyed
+}
+
+// IsDestroyed will return true if the device has been destroyed (usually by closing it).
+func (d *FakeDevice) IsDestroyed() bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.destroyed
+}
+
+// HasEventType will return true if the given event type has been registered.
+func (d *FakeDevice) HasEventType(evType uint16) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.evBits[evType]
+}
+
+// HasCode will return true if the given code of the given event type has been registered.
+func (d *FakeDevice) HasCode(evType uint16, code uint16) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.codes[evType][code]
+}
+
+// Codes will return all registered codes of the given event type in ascending order.
+func (d *FakeDevice) Codes(evType uint16) []uint16 {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	var codes []uint16
+	for code := range d.codes[evType] {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+	return codes
+}
+
+// HasProperty will return true if the given input property has been set.
+func (d *FakeDevice) HasProperty(prop uint16) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.props[prop]
+}
+
+// Axis will return the configuration of the given absolute axis and whether it has been set up at all.
+func (d *FakeDevice) Axis(code uint16) (AxisConfig, bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	info, ok := d.absinfo[code]
+	return AxisConfig{
+		Code:       code,
+		Min:        info.Minimum,
+		Max:        info.Maximum,
+		Fuzz:       info.Fuzz,
+		Flat:       info.Flat,
+		Resolution: info.Resolution,
+	}, ok
+}
+
+// Events will return all events sent by the device (including SYN_REPORTs) since it was created or since the last
+// call to ClearEvents.
+func (d *FakeDevice) Events() []Event {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return append([]Event(nil), d.events...)
+}
+
+// ClearEvents will discard all recorded events.
+func (d *FakeDevice) ClearEvents() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.events = nil
+}
+
+// Send will pass the given events to the device, as if they were sent by the system (e.g. led changes).
+func (d *FakeDevice) Send(events ...Event) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	for _, ev := range events {
+		d.pending = append(d.pending, inputEvent{Type: ev.Type, Code: ev.Code, Value: ev.Value})
+	}
+	d.cond.Broadcast()
+}
+
+// UploadEffect will upload the given force feedback effect to the device, like an application does using EVIOCSFF.
+// The device is asked to complete the upload (UI_FF_UPLOAD) and the error it reports is returned. The ID of the
+// effect is passed on unchanged.
+func (d *FakeDevice) UploadEffect(effect FFEffect) error {
+	request := &fakeFFRequest{upload: &uinputFfUpload{Effect: encodeEffect(effect)}}
+	err := d.requestFF(uiFfUpload, request)
+	if err != nil {
+		return err
+	}
+	return retvalError(request.upload.Retval)
+}
+
+// EraseEffect will remove the force feedback effect with the given ID from the device, like an application does
+// using EVIOCRMFF. The device is asked to complete the removal (UI_FF_ERASE) and the error it reports is returned.
+func (d *FakeDevice) EraseEffect(id int16) error {
+	request := &fakeFFRequest{erase: &uinputFfErase{EffectID: uint32(id)}}
+	err := d.requestFF(uiFfErase, request)
+	if err != nil {
+		return err
+	}
+	return retvalError(request.erase.Retval)
+}
+
+// requestFF will pass the request to the device and wait until the device has completed it.
+func (d *FakeDevice) requestFF(code uint16, request *fakeFFRequest) error {
+	d.mutex.Lock()
+	if d.closed || !d.created {
+		d.mutex.Unlock()
+		return syscall.ENODEV
+	}
+	if !d.evBits[evFf] {
+		d.mutex.Unlock()
+		return syscall.EINVAL
+	}
+	d.ffID++
+	id := d.ffID
+	if request.upload != nil {
+		request.upload.RequestID = id
+	} else {
+		request.erase.RequestID = id
+	}
+	request.done = make(chan struct{})
+	d.ffPending[id] = request
+	d.pending = append(d.pending, inputEvent{Type: evUinput, Code: code, Value: int32(id)})
+	d.cond.Broadcast()
+	d.mutex.Unlock()
+
+	select {
+	case <-request.done:
+		return nil
+	case <-time.After(fakeFFTimeout):
+		d.mutex.Lock()
+		delete(d.ffPending, id)
+		d.mutex.Unlock()
+		return syscall.ETIMEDOUT
+	}
+}
+
+func retvalError(retval int32) error {
+	if retval != 0 {
+		return syscall.Errno(-retval)
+	}
+	return nil
+}
+
+// ExpectEvents will report an error if the recorded events do not match the expected events. Afterwards, all
+// recorded events are discarded.
+func (d *FakeDevice) ExpectEvents(t TestingT, expected ...Event) {
+	t.Helper()
+	actual := d.Events()
+	d.ClearEvents()
+	if len(actual) != len(expected) {
+		t.Errorf("expected %d events, but got %d: %+v", len(expected), len(actual), actual)
+		return
+	}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Errorf("expected event %d to be %+v, but got %+v", i, expected[i], actual[i])
+		}
+	}
+}
+
+// ExpectNoEvents will report an error if any events have been recorded.
+func (d *FakeDevice) ExpectNoEvents(t TestingT) {
+	t.Helper()
+	d.ExpectEvents(t)
+}
+
+// ExpectCodes will report an error if the registered codes of the given event type do not match the expected codes.
+func (d *FakeDevice) ExpectCodes(t TestingT, evType uint16, expected ...uint16) {
+	t.Helper()
+	sorted := append([]uint16(nil), expected...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	actual := d.Codes(evType)
+	if !reflect.DeepEqual(actual, sorted) && (len(actual) != 0 || len(sorted) != 0) {
+		t.Errorf("expected codes %v for event type %d, but got %v", sorted, evType, actual)
+	}
+}
+
+// fakeFile is a uinputFile backed by a FakeDevice.
+type fakeFile struct {
+	dev *FakeDevice
+}
+
+func (f *fakeFile) Read(p []byte) (int, error) {
+	d := f.dev
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	size := binary.Size(inputEvent{})
+	if len(p) < size {
+		return 0, io.ErrShortBuffer
+	}
+	for len(d.pending) == 0 && !d.closed {
+		d.cond.Wait()
+	}
+	if d.closed {
+		return 0, os.ErrClosed
+	}
+
+	buf := new(bytes.Buffer)
+	n := 0
+	for n < len(d.pending) && (n+1)*size <= len(p) {
+		_ = binary.Write(buf, binary.LittleEndian, d.pending[n])
+		n++
+	}
+	d.pending = d.pending[n:]
+	return copy(p, buf.Bytes()), nil
+}
+
+func (f *fakeFile) Write(p []byte) (int, error) {
+	d := f.dev
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.closed {
+		return 0, os.ErrClosed
+	}
+	if !d.created {
+		return d.writeUserDev(p)
+	}
+
+	size := binary.Size(inputEvent{})
+	if len(p)%size != 0 {
+		return 0, syscall.EINVAL
+	}
+	reader := bytes.NewReader(p)
+	for reader.Len() > 0 {
+		var ev inputEvent
+		_ = binary.Read(reader, binary.LittleEndian, &ev)
+		d.events = append(d.events, Event{Type: ev.Type, Code: ev.Code, Value: ev.Value})
+	}
+	return len(p), nil
+}
+
+// writeUserDev handles the legacy way of setting up a device by writing a uinput_user_dev struct.
+func (d *FakeDevice) writeUserDev(p []byte) (int, error) {
+	var dev uinputUserDev
+	if len(p) != binary.Size(dev) {
+		return 0, syscall.EINVAL
+	}
+	_ = binary.Read(bytes.NewReader(p), binary.LittleEndian, &dev)
+
+	d.setup = uinputSetup{ID: dev.ID, Name: dev.Name, EffectsMax: dev.EffectsMax}
+	for code := range d.codes[evAbs] {
+		if code < absSize {
+			d.absinfo[code] = absInfo{
+				Minimum: dev.Absmin[code],
+				Maximum: dev.Absmax[code],
+				Fuzz:    dev.Absfuzz[code],
+				Flat:    dev.Absflat[code],
+			}
+		}
+	}
+	d.setupDone = true
+	return len(p), nil
+}
+
+func (f *fakeFile) Close() error {
+	d := f.dev
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.closed {
+		return os.ErrClosed
+	}
+	d.closed = true
+	if d.created {
+		d.destroyed = true
+	}
+	for id, request := range d.ffPending {
+		if request.upload != nil {
+			request.upload.Retval = -int32(syscall.ENODEV)
+		} else {
+			request.erase.Retval = -int32(syscall.ENODEV)
+		}
+		delete(d.ffPending, id)
+		close(request.done)
+	}
+	d.cond.Broadcast()
+	return nil
+}
+
+func (f *fakeFile) ioctl(cmd uintptr, arg interface{}) error {
+	d := f.dev
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.closed {
+		return os.ErrClosed
+	}
+
+	switch cmd {
+	case uiGetVersion:
+		*arg.(*uint32) = uinputVersionDevSetup
+		return nil
+	case uiGetSysname:
+		if !d.created {
+			return syscall.ENOENT
+		}
+		copy(arg.([]byte), d.sysname)
+		return nil
+	case uiDevDestroy:
+		if d.created {
+			d.destroyed = true
+		}
+		return nil
+	case uiBeginFfUpload:
+		upload := arg.(*uinputFfUpload)
+		request, ok := d.ffPending[upload.RequestID]
+		if !ok || request.upload == nil {
+			return syscall.EINVAL
+		}
+		*upload = *request.upload
+		return nil
+	case uiEndFfUpload:
+		upload := arg.(*uinputFfUpload)
+		request, ok := d.ffPending[upload.RequestID]
+		if !ok || request.upload == nil {
+			return syscall.EINVAL
+		}
+		request.upload.Retval = upload.Retval
+		delete(d.ffPending, upload.RequestID)
+		close(request.done)
+		return nil
+	case uiBeginFfErase:
+		erase := arg.(*uinputFfErase)
+		request, ok := d.ffPending[erase.RequestID]
+		if !ok || request.erase == nil {
+			return syscall.EINVAL
+		}
+		*erase = *request.erase
+		return nil
+	case uiEndFfErase:
+		erase := arg.(*uinputFfErase)
+		request, ok := d.ffPending[erase.RequestID]
+		if !ok || request.erase == nil {
+			return syscall.EINVAL
+		}
+		request.erase.Retval = erase.Retval
+		delete(d.ffPending, erase.RequestID)
+		close(request.done)
+		return nil
+	}
+
+	if d.created {
+		return syscall.EINVAL
+	}
+
+	switch cmd {
+	case uiSetEvBit:
+		d.evBits[uint16(arg.(uintptr))] = true
+	case uiSetKeyBit, uiSetRelBit, uiSetAbsBit, uiSetMscBit, uiSetLedBit, uiSetSndBit, uiSetFfBit, uiSetSwBit:
+		d.setCode(fakeSetBitTypes[cmd], uint16(arg.(uintptr)))
+	case uiSetPhys:
+		phys := arg.([]byte)
+		d.phys = string(phys[:bytes.IndexByte(append(phys, 0), 0)])
+	case uiSetPropBit:
+		if arg.(uintptr) > propMax {
+			return syscall.EINVAL
+		}
+		d.props[uint16(arg.(uintptr))] = true
+	case uiAbsSetup:
+		setup := arg.(*uinputAbsSetup)
+		d.setCode(evAbs, setup.Code)
+		d.absinfo[setup.Code] = setup.Absinfo
+	case uiDevSetup:
+		d.setup = *arg.(*uinputSetup)
+		d.setupDone = true
+	case uiDevCreate:
+		if !d.setupDone {
+			return syscall.EINVAL
+		}
+		d.created = true
+	default:
+		return syscall.EINVAL
+	}
+	return nil
+}
+
+func (d *FakeDevice) setCode(evType uint16, code uint16) {
+	if d.codes[evType] == nil {
+		d.codes[evType] = make(map[uint16]bool)
+	}
+	d.codes[evType][code] = true
+}
+
+var fakeSetBitTypes = map[uintptr]uint16{
+	uiSetKeyBit: evKey,
+	uiSetRelBit: evRel,
+	uiSetAbsBit: evAbs,
+	uiSetMscBit: evMsc,
+	uiSetLedBit: evLed,
+	uiSetSndBit: evSnd,
+	uiSetFfBit:  evFf,
+	uiSetSwBit:  evSw,
+}
@@ -0,0 +1,153 @@
+package uinput
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeKeyboardRecordsCapabilitiesAndEvents(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	vk, err := CreateKeyboard(fake.Path(), []byte("Test Keyboard"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	defer vk.Close()
+
+	dev := fake.Device("Test Keyboard")
+	if dev == nil {
+		t.Fatalf("Expected the fake to hold a device named 'Test Keyboard'")
+	}
+	if !dev.IsCreated() {
+		t.Fatalf("Expected the device to be created")
+	}
+	if !dev.HasEventType(EvKey) || !dev.HasCode(EvKey, KeyA) || !dev.HasCode(EvLed, LedCapsLock) {
+		t.Fatalf("Expected the keyboard to register keys and leds")
+	}
+
+	err = vk.KeyPress(KeyA)
+	if err != nil {
+		t.Fatalf("Failed to send key press. Last error was: %s\n", err)
+	}
+	dev.ExpectEvents(t,
+		Event{Type: EvKey, Code: KeyA, Value: 1},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvKey, Code: KeyA, Value: 0},
+		Event{Type: EvSyn, Code: SynReport})
+
+	syspath, err := vk.FetchSyspath()
+	if err != nil || syspath != "/sys/devices/virtual/input/input0" {
+		t.Fatalf("Unexpected syspath '%s' (error: %v)", syspath, err)
+	}
+}
+
+func TestFakeMouseFrameIsSynchronizedOnce(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	mouse, err := CreateMouse(fake.Path(), []byte("Test Mouse"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual mouse. Last error was: %s\n", err)
+	}
+	defer mouse.Close()
+
+	frame := mouse.NewFrame()
+	frame.Emit(EvRel, RelX, 10)
+	frame.Emit(EvRel, RelY, -5)
+	err = frame.Flush()
+	if err != nil {
+		t.Fatalf("Failed to flush frame. Last error was: %s\n", err)
+	}
+	fake.Device("Test Mouse").ExpectEvents(t,
+		Event{Type: EvRel, Code: RelX, Value: 10},
+		Event{Type: EvRel, Code: RelY, Value: -5},
+		Event{Type: EvSyn, Code: SynReport})
+}
+
+func TestFakeGamepadRecordsAxisConfiguration(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	gamepad, err := CreateGamepadWithAxes(fake.Path(), []byte("Test Gamepad"), 0x0fff, 0x0ff2,
+		[]AxisConfig{{Code: AbsX, Min: 0, Max: 255, Flat: 15}})
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+	defer gamepad.Close()
+
+	dev := fake.Device("Test Gamepad")
+	id := dev.ID()
+	if id.Vendor != 0x0fff || id.Product != 0x0ff2 {
+		t.Fatalf("Unexpected device id %+v", id)
+	}
+	axis, ok := dev.Axis(AbsX)
+	if !ok || axis.Min != 0 || axis.Max != 255 || axis.Flat != 15 {
+		t.Fatalf("Unexpected axis configuration %+v", axis)
+	}
+
+	err = gamepad.LeftStickMoveX(1)
+	if err != nil {
+		t.Fatalf("Failed to move the left stick. Last error was: %s\n", err)
+	}
+	dev.ExpectEvents(t,
+		Event{Type: EvAbs, Code: AbsX, Value: 255},
+		Event{Type: EvSyn, Code: SynReport})
+}
+
+func TestFakeDeliversEventsToDevice(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	vk, err := CreateKeyboard(fake.Path(), []byte("Test Keyboard"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	defer vk.Close()
+
+	received := make(chan Event, 1)
+	vk.SetEventHandler(func(event Event) {
+		received <- event
+	})
+	fake.Device("Test Keyboard").Send(Event{Type: EvLed, Code: LedCapsLock, Value: 1})
+
+	select {
+	case <-received:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected the led event to be delivered to the handler")
+	}
+	if !vk.LEDs().IsOn(LedCapsLock) {
+		t.Fatalf("Expected caps lock to be on")
+	}
+}
+
+func TestFakeDeviceIsDestroyedOnClose(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	vk, err := CreateKeyboard(fake.Path(), []byte("Test Keyboard"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	err = vk.Close()
+	if err != nil {
+		t.Fatalf("Failed to close the virtual keyboard. Last error was: %s\n", err)
+	}
+	if !fake.Device("Test Keyboard").IsDestroyed() {
+		t.Fatalf("Expected the device to be destroyed")
+	}
+	err = vk.KeyPress(KeyA)
+	if err == nil {
+		t.Fatalf("Expected KeyPress to fail on closed device, but no error was returned")
+	}
+}
+
+func TestFakeCreationFailsAfterClose(t *testing.T) {
+	fake := NewFake()
+	_ = fake.Close()
+
+	_, err := CreateKeyboard(fake.Path(), []byte("Test Keyboard"))
+	if err == nil {
+		t.Fatalf("Expected keyboard creation to fail on closed fake")
+	}
+}
@@ -0,0 +1,275 @@
+package uinput
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sync"
+)
+
+// FFEffectType specifies the kind of force feedback effect requested by an application.
+type FFEffectType uint16
+
+// force feedback effect types as specified in input.h
+const (
+	FFRumble   FFEffectType = 0x50
+	FFPeriodic FFEffectType = 0x51
+	FFConstant FFEffectType = 0x52
+)
+
+// FFWaveform specifies the wave form of a periodic effect.
+type FFWaveform uint16
+
+// periodic effect wave forms as specified in input.h
+const (
+	FFSquare   FFWaveform = 0x58
+	FFTriangle FFWaveform = 0x59
+	FFSine     FFWaveform = 0x5a
+	FFSawUp    FFWaveform = 0x5b
+	FFSawDown  FFWaveform = 0x5c
+)
+
+// maximum number of effects that may be uploaded to a single device at the same time
+const ffEffectsMax = 16
+
+// FFEnvelope describes the attack and fade of constant and periodic effects.
+// Lengths are given in milliseconds, levels range from 0 to 0x7fff.
+type FFEnvelope struct {
+	AttackLength uint16
+	AttackLevel  uint16
+	FadeLength   uint16
+	FadeLevel    uint16
+}
+
+// FFRumbleEffect describes a rumble effect. The strong magnitude corresponds to the heavy (low frequency) motor,
+// the weak magnitude to the light (high frequency) motor.
+type FFRumbleEffect struct {
+	StrongMagnitude uint16
+	WeakMagnitude   uint16
+}
+
+// FFConstantEffect describes a constant force effect.
+type FFConstantEffect struct {
+	Level    int16
+	Envelope FFEnvelope
+}
+
+// FFPeriodicEffect describes a periodic effect. The period is given in milliseconds.
+type FFPeriodicEffect struct {
+	Waveform  FFWaveform
+	Period    uint16
+	Magnitude int16
+	Offset    int16
+	Phase     uint16
+	Envelope  FFEnvelope
+}
+
+// FFEffect is a force feedback effect as uploaded by an application. Only the field matching the Type
+// (Rumble, Constant or Periodic) carries meaningful data. Length and Delay are given in milliseconds.
+type FFEffect struct {
+	ID        int16
+	Type      FFEffectType
+	Direction uint16
+	Length    uint16
+	Delay     uint16
+	Rumble    FFRumbleEffect
+	Constant  FFConstantEffect
+	Periodic  FFPeriodicEffect
+}
+
+// FFEventType specifies what happened to a force feedback effect.
+type FFEventType int
+
+const (
+	// FFUpload signals that an effect was uploaded or updated.
+	FFUpload FFEventType = iota + 1
+	// FFErase signals that an effect was removed from the device.
+	FFErase
+	// FFPlay signals that an effect should start playing. Value holds the number of repetitions.
+	FFPlay
+	// FFStop signals that an effect should stop playing.
+	FFStop
+	// FFGain signals a change of the overall gain. Value holds the new gain (0 - 0xffff).
+	FFGain
+	// FFAutocenter signals a change of the autocenter strength. Value holds the new strength (0 - 0xffff).
+	FFAutocenter
+)
+
+// FFEvent is passed to the FFHandler whenever an application interacts with the force feedback
+// capabilities of a device.
+type FFEvent struct {
+	Type   FFEventType
+	Effect FFEffect
+	Value  int32
+}
+
+// FFHandler receives force feedback events. It is invoked from a separate goroutine and should return quickly,
+// since the application that uploads an effect is blocked until the handler returns.
+type FFHandler func(event FFEvent)
+
+type forceFeedback struct {
+	deviceFile uinputFile
+	handler    FFHandler
+	mutex      sync.Mutex
+	effects    map[int16]FFEffect
+}
+
+func newForceFeedback(deviceFile uinputFile, handler FFHandler) *forceFeedback {
+	return &forceFeedback{deviceFile: deviceFile, handler: handler, effects: make(map[int16]FFEffect)}
+}
+
+// force feedback effects advertised by gamepads created using CreateGamepadWithFF
+var defaultFFEffects = []uint16{
+	uint16(FFRumble),
+	uint16(FFPeriodic),
+	uint16(FFConstant),
+	uint16(FFSquare),
+	uint16(FFTriangle),
+	uint16(FFSine),
+	uint16(FFSawUp),
+	uint16(FFSawDown),
+	ffGain,
+}
+
+// force feedback effects advertised by drivers that only support rumble, which the kernel uses to emulate simple
+// periodic effects (see ff-memless.c)
+var memlessFFEffects = []uint16{
+	uint16(FFRumble),
+	uint16(FFPeriodic),
+	uint16(FFSquare),
+	uint16(FFTriangle),
+	uint16(FFSine),
+	ffGain,
+}
+
+func registerForceFeedback(deviceFile uinputFile, effects []uint16) error {
+	err := registerDevice(deviceFile, uintptr(evFf))
+	if err != nil {
+		return err
+	}
+
+	for _, code := range effects {
+		err = ioctl(deviceFile, uiSetFfBit, uintptr(code))
+		if err != nil {
+			return fmt.Errorf("failed to register force feedback effect %v: %v", code, err)
+		}
+	}
+	return nil
+}
+
+func (ff *forceFeedback) handleEvent(ev inputEvent) {
+	switch ev.Type {
+	case evUinput:
+		switch ev.Code {
+		case uiFfUpload:
+			ff.upload(uint32(ev.Value))
+		case uiFfErase:
+			ff.erase(uint32(ev.Value))
+		}
+	case evFf:
+		switch ev.Code {
+		case ffGain:
+			ff.notify(FFEvent{Type: FFGain, Value: ev.Value})
+		case ffAutocenter:
+			ff.notify(FFEvent{Type: FFAutocenter, Value: ev.Value})
+		default:
+			ff.mutex.Lock()
+			effect, ok := ff.effects[int16(ev.Code)]
+			ff.mutex.Unlock()
+			if !ok {
+				return
+			}
+			if ev.Value > 0 {
+				ff.notify(FFEvent{Type: FFPlay, Effect: effect, Value: ev.Value})
+			} else {
+				ff.notify(FFEvent{Type: FFStop, Effect: effect})
+			}
+		}
+	}
+}
+
+func (ff *forceFeedback) upload(requestID uint32) {
+	upload := uinputFfUpload{RequestID: requestID}
+	err := ioctl(ff.deviceFile, uiBeginFfUpload, &upload)
+	if err != nil {
+		return
+	}
+
+	effect := decodeEffect(upload.Effect)
+	ff.mutex.Lock()
+	ff.effects[effect.ID] = effect
+	ff.mutex.Unlock()
+	ff.notify(FFEvent{Type: FFUpload, Effect: effect})
+
+	upload.Retval = 0
+	_ = ioctl(ff.deviceFile, uiEndFfUpload, &upload)
+}
+
+func (ff *forceFeedback) erase(requestID uint32) {
+	erase := uinputFfErase{RequestID: requestID}
+	err := ioctl(ff.deviceFile, uiBeginFfErase, &erase)
+	if err != nil {
+		return
+	}
+
+	id := int16(erase.EffectID)
+	ff.mutex.Lock()
+	effect, ok := ff.effects[id]
+	delete(ff.effects, id)
+	ff.mutex.Unlock()
+	if ok {
+		ff.notify(FFEvent{Type: FFErase, Effect: effect})
+	}
+
+	erase.Retval = 0
+	_ = ioctl(ff.deviceFile, uiEndFfErase, &erase)
+}
+
+func (ff *forceFeedback) notify(event FFEvent) {
+	if ff.handler != nil {
+		ff.handler(event)
+	}
+}
+
+func decodeEffect(raw ffEffect) FFEffect {
+	effect := FFEffect{
+		ID:        raw.ID,
+		Type:      FFEffectType(raw.Type),
+		Direction: raw.Direction,
+		Length:    raw.Replay.Length,
+		Delay:     raw.Replay.Delay,
+	}
+
+	reader := bytes.NewReader(raw.U[:])
+	switch effect.Type {
+	case FFRumble:
+		_ = binary.Read(reader, binary.LittleEndian, &effect.Rumble)
+	case FFConstant:
+		_ = binary.Read(reader, binary.LittleEndian, &effect.Constant)
+	case FFPeriodic:
+		_ = binary.Read(reader, binary.LittleEndian, &effect.Periodic)
+	}
+	return effect
+}
+
+// encodeEffect is the counterpart of decodeEffect, which is used to pass effects to fake devices.
+func encodeEffect(effect FFEffect) ffEffect {
+	raw := ffEffect{
+		Type:      uint16(effect.Type),
+		ID:        effect.ID,
+		Direction: effect.Direction,
+		Replay:    ffReplay{Length: effect.Length, Delay: effect.Delay},
+	}
+
+	buf := new(bytes.Buffer)
+	switch effect.Type {
+	case FFRumble:
+		_ = binary.Write(buf, binary.LittleEndian, effect.Rumble)
+	case FFConstant:
+		_ = binary.Write(buf, binary.LittleEndian, effect.Constant)
+	case FFPeriodic:
+		_ = binary.Write(buf, binary.LittleEndian, effect.Periodic)
+	}
+	copy(raw.U[:], buf.Bytes())
+	return raw
+}
@@ -0,0 +1,134 @@
+package uinput
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+func TestGamepadWithFFCreation(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	vg, err := CreateGamepadWithFF(fake.Path(), []byte("Rumbling gophers"), 0xDEAD, 0xBEEF, func(event FFEvent) {})
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+
+	err = vg.ButtonPress(ButtonSouth)
+	if err != nil {
+		t.Fatalf("Failed to send button press. Last error was: %s\n", err)
+	}
+
+	err = vg.Close()
+	if err != nil {
+		t.Fatalf("Failed to close device. Last error was: %s\n", err)
+	}
+}
+
+func TestGamepadWithFFHandlesUploadPlayAndErase(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	received := make(chan FFEvent, 4)
+	vg, err := CreateGamepadWithFF(fake.Path(), []byte("Rumbling gophers"), 0xDEAD, 0xBEEF, func(event FFEvent) {
+		received <- event
+	})
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+	defer vg.Close()
+	dev := fake.Device("Rumbling gophers")
+
+	effect := FFEffect{ID: 2, Type: FFRumble, Length: 500, Delay: 10,
+		Rumble: FFRumbleEffect{StrongMagnitude: 0xc000, WeakMagnitude: 0x4000}}
+	err = dev.UploadEffect(effect)
+	if err != nil {
+		t.Fatalf("Failed to upload the effect. Last error was: %s\n", err)
+	}
+	expectFFEvent(t, received, FFEvent{Type: FFUpload, Effect: effect})
+
+	dev.Send(Event{Type: EvFf, Code: 2, Value: 3})
+	expectFFEvent(t, received, FFEvent{Type: FFPlay, Effect: effect, Value: 3})
+	dev.Send(Event{Type: EvFf, Code: 2, Value: 0})
+	expectFFEvent(t, received, FFEvent{Type: FFStop, Effect: effect})
+
+	err = dev.EraseEffect(2)
+	if err != nil {
+		t.Fatalf("Failed to erase the effect. Last error was: %s\n", err)
+	}
+	expectFFEvent(t, received, FFEvent{Type: FFErase, Effect: effect})
+
+	// the effect is gone, so playing it has no effect
+	dev.Send(Event{Type: EvFf, Code: 2, Value: 1})
+	dev.Send(Event{Type: EvFf, Code: ffGain, Value: 0x8000})
+	expectFFEvent(t, received, FFEvent{Type: FFGain, Value: 0x8000})
+}
+
+func TestFFUploadFailsWithoutForceFeedback(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	vg, err := CreateGamepad(fake.Path(), []byte("Test Gamepad"), 0xDEAD, 0xBEEF)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+	defer vg.Close()
+
+	err = fake.Device("Test Gamepad").UploadEffect(FFEffect{Type: FFRumble})
+	if err == nil {
+		t.Fatalf("Expected the upload to fail, since the gamepad does not support force feedback")
+	}
+}
+
+func expectFFEvent(t *testing.T, received chan FFEvent, expected FFEvent) {
+	t.Helper()
+	select {
+	case actual := <-received:
+		if actual != expected {
+			t.Fatalf("Expected: %+v\nActual: %+v", expected, actual)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Expected the force feedback event %+v to be delivered to the handler", expected)
+	}
+}
+
+func TestFFRequestStructsMatchKernelLayout(t *testing.T) {
+	if size := unsafe.Sizeof(uinputFfUpload{}); size != (uiBeginFfUpload>>16)&0x3fff {
+		t.Fatalf("Expected size of uinputFfUpload to match ioctl definition, but got %d", size)
+	}
+	if size := unsafe.Sizeof(uinputFfErase{}); size != (uiBeginFfErase>>16)&0x3fff {
+		t.Fatalf("Expected size of uinputFfErase to match ioctl definition, but got %d", size)
+	}
+}
+
+func TestDecodeRumbleEffect(t *testing.T) {
+	raw := ffEffect{Type: uint16(FFRumble), ID: 3, Replay: ffReplay{Length: 500, Delay: 10}}
+	buf := new(bytes.Buffer)
+	_ = binary.Write(buf, binary.LittleEndian, FFRumbleEffect{StrongMagnitude: 0xc000, WeakMagnitude: 0x4000})
+	copy(raw.U[:], buf.Bytes())
+
+	effect := decodeEffect(raw)
+	if effect.ID != 3 || effect.Type != FFRumble || effect.Length != 500 || effect.Delay != 10 {
+		t.Fatalf("Unexpected effect header: %+v", effect)
+	}
+	if effect.Rumble.StrongMagnitude != 0xc000 || effect.Rumble.WeakMagnitude != 0x4000 {
+		t.Fatalf("Unexpected rumble magnitudes: %+v", effect.Rumble)
+	}
+}
+
+func TestDecodePeriodicEffect(t *testing.T) {
+	raw := ffEffect{Type: uint16(FFPeriodic)}
+	expected := FFPeriodicEffect{Waveform: FFSine, Period: 100, Magnitude: -200, Offset: 5, Phase: 90,
+		Envelope: FFEnvelope{AttackLength: 1, AttackLevel: 2, FadeLength: 3, FadeLevel: 4}}
+	buf := new(bytes.Buffer)
+	_ = binary.Write(buf, binary.LittleEndian, expected)
+	copy(raw.U[:], buf.Bytes())
+
+	effect := decodeEffect(raw)
+	if effect.Periodic != expected {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, effect.Periodic)
+	}
+}
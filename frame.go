@@ -0,0 +1,74 @@
+package uinput
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// An EventEmitter allows to send raw events (see the Ev* constants and the related codes in keycodes.go) to a device.
+type EventEmitter interface {
+	// Emit will send a single raw event to the device, immediately followed by a SYN_REPORT.
+	Emit(evType uint16, code uint16, value int32) error
+
+	// NewFrame will create an empty frame that may be used to send multiple events at once.
+	NewFrame() *Frame
+}
+
+// A Frame collects events that are written to the device in a single write, followed by a single SYN_REPORT.
+// Since the kernel only applies events upon synchronization, all events of a frame will be perceived as one
+// atomic change of the device state (e.g. moving along the x and y-axis while pressing a button).
+// A Frame is not safe for concurrent use.
+type Frame struct {
+	deviceFile uinputFile
+	events     []inputEvent
+}
+
+func newFrame(deviceFile uinputFile) *Frame {
+	return &Frame{deviceFile: deviceFile}
+}
+
+// Emit will add a raw event to the frame. The event will not be sent until Flush is called.
+func (f *Frame) Emit(evType uint16, code uint16, value int32) {
+	f.events = append(f.events, inputEvent{Type: evType, Code: code, Value: value})
+}
+
+// Len will return the number of events currently held by the frame.
+func (f *Frame) Len() int {
+	return len(f.events)
+}
+
+// Flush will send all events of the frame followed by a SYN_REPORT. Afterwards, the frame is empty and may be reused.
+// Flushing an empty frame is a no-op.
+func (f *Frame) Flush() error {
+	if len(f.events) == 0 {
+		return nil
+	}
+	err := writeEvents(f.deviceFile, append(f.events, inputEvent{Type: evSyn, Code: synReport}))
+	f.events = f.events[:0]
+	return err
+}
+
+func emitEvent(deviceFile uinputFile, evType uint16, code uint16, value int32) error {
+	return writeEvents(deviceFile, []inputEvent{
+		{Type: evType, Code: code, Value: value},
+		{Type: evSyn, Code: synReport},
+	})
+}
+
+// writeEvents will send the given events to the device using a single write.
+func writeEvents(deviceFile uinputFile, events []inputEvent) error {
+	buf := new(bytes.Buffer)
+	for _, ev := range events {
+		evBuf, err := inputEventToBuffer(ev)
+		if err != nil {
+			return fmt.Errorf("failed to build event: %v", err)
+		}
+		buf.Write(evBuf)
+	}
+
+	_, err := deviceFile.Write(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to write events to device file: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package uinput
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestMouseFrame(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	relDev, err := CreateMouse(fake.Path(), []byte("Test Frame Mouse"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual mouse. Last error was: %s\n", err)
+	}
+	defer relDev.Close()
+
+	frame := relDev.NewFrame()
+	frame.Emit(EvRel, RelX, 10)
+	frame.Emit(EvRel, RelY, -10)
+	frame.Emit(EvKey, evMouseBtnLeft, btnStatePressed)
+	frame.Emit(EvRel, RelWheel, 1)
+	err = frame.Flush()
+	if err != nil {
+		t.Fatalf("Failed to flush frame. Last error was: %s\n", err)
+	}
+
+	err = relDev.Emit(EvKey, evMouseBtnLeft, btnStateReleased)
+	if err != nil {
+		t.Fatalf("Failed to emit raw event. Last error was: %s\n", err)
+	}
+
+	// the whole frame is synchronized by a single SYN_REPORT
+	fake.Device("Test Frame Mouse").ExpectEvents(t,
+		Event{Type: EvRel, Code: RelX, Value: 10},
+		Event{Type: EvRel, Code: RelY, Value: -10},
+		Event{Type: EvKey, Code: evMouseBtnLeft, Value: btnStatePressed},
+		Event{Type: EvRel, Code: RelWheel, Value: 1},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvKey, Code: evMouseBtnLeft, Value: btnStateReleased},
+		Event{Type: EvSyn, Code: SynReport})
+}
+
+func TestFrameIsWrittenAtOnceWithSingleSync(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-frame-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	frame := newFrame(osFile{file})
+	err = frame.Flush()
+	if err != nil {
+		t.Fatalf("Failed to flush empty frame: %v", err)
+	}
+
+	frame.Emit(EvRel, RelX, 10)
+	frame.Emit(EvRel, RelY, -10)
+	if frame.Len() != 2 {
+		t.Fatalf("Expected 2 queued events, but got %d", frame.Len())
+	}
+	err = frame.Flush()
+	if err != nil {
+		t.Fatalf("Failed to flush frame: %v", err)
+	}
+	if frame.Len() != 0 {
+		t.Fatalf("Expected frame to be empty after flush, but got %d events", frame.Len())
+	}
+
+	events := readEvents(t, file.Name())
+	expected := []inputEvent{
+		{Type: evRel, Code: relX, Value: 10},
+		{Type: evRel, Code: relY, Value: -10},
+		{Type: evSyn, Code: synReport},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, but got %d", len(expected), len(events))
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Fatalf("Expected event %d to be %+v, but got %+v", i, expected[i], events[i])
+		}
+	}
+}
+
+func TestFrameFlushFailsOnClosedFile(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-frame-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	_ = file.Close()
+
+	frame := newFrame(osFile{file})
+	frame.Emit(EvRel, RelX, 10)
+	err = frame.Flush()
+	if err == nil {
+		t.Fatalf("Expected error due to closed file, but no error was returned.")
+	}
+}
+
+func readEvents(t *testing.T, path string) []inputEvent {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read events: %v", err)
+	}
+
+	var events []inputEvent
+	reader := bytes.NewReader(data)
+	for reader.Len() > 0 {
+		var ev inputEvent
+		err = binary.Read(reader, binary.LittleEndian, &ev)
+		if err != nil {
+			t.Fatalf("Failed to decode event: %v", err)
+		}
+		events = append(events, ev)
+	}
+	return events
+}
@@ -4,7 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"os"
+	"sort"
+	"sync"
 )
 
 const MaximumAxisValue = 32767
@@ -19,6 +20,13 @@ const (
 	HatRight
 )
 
+var oppositeHatDirections = map[HatDirection]HatDirection{
+	HatUp:    HatDown,
+	HatDown:  HatUp,
+	HatLeft:  HatRight,
+	HatRight: HatLeft,
+}
+
 type HatAction int
 
 const (
@@ -51,7 +59,8 @@ type Gamepad interface {
 	// RightStickMoveY performs a movement of the right stick along the y-axis
 	RightStickMoveY(value float32) error
 
-	// SendStickAxisEvent performs a movement of a stick along an axis
+	// SendStickAxisEvent performs a movement of a stick along an axis. The normalized value (-1.0:1.0) is mapped
+	// onto the range the axis was configured with.
 	SendStickAxisEvent(absCode uint16, value float32) error
 
 	// LeftStickMove moves the left stick along the x and y-axis
@@ -62,25 +71,90 @@ type Gamepad interface {
 	// SendStickEvent moves a stick along the x and y-axis
 	SendStickEvent(values map[uint16]float32) error
 
+	// LeftTrigger pulls the left analog trigger (absZ). The normalized value (0.0:1.0) is mapped onto the range the
+	// axis was configured with, 0 meaning that the trigger is released. If the gamepad was created using
+	// WithTriggerThreshold, ButtonTriggerLeft is pressed and released along with the trigger.
+	LeftTrigger(value float32) error
+	// RightTrigger pulls the right analog trigger (absRZ), see LeftTrigger.
+	RightTrigger(value float32) error
+
+	// SetState updates all buttons, sticks, triggers and the hat at once, sending only the controls that changed since
+	// the last update within a single frame.
+	SetState(state GamepadState) error
+
 	// HatPress will issue a hat-press event in the given direction
 	HatPress(direction HatDirection) error
-	// HatRelease will issue a hat-release event in the given direction
+	// HatRelease will issue a hat-release event in the given direction. If the opposite direction is still held down,
+	// the hat moves there.
 	HatRelease(direction HatDirection) error
 
+	// HatMove moves the hat with the given index (0 to 3) to the given position along the x-axis (-1 left, 1 right)
+	// and y-axis (-1 up, 1 down) within a single frame. Hats other than the first one need to be registered using
+	// WithHats.
+	HatMove(hat int, x int32, y int32) error
+	// HatSet moves the hat with the given index to one of its eight directions (or its center), see HatMove.
+	HatSet(hat int, position HatPosition) error
+
 	// FetchSysPath will return the syspath to the device file.
 	FetchSyspath() (string, error)
 
+	EventEmitter
+
+	EventReceiver
+
+	Releaser
+
 	io.Closer
 }
 
 type vGamepad struct {
 	name       []byte
-	deviceFile *os.File
+	id         InputID
+	phys       string
+	deviceFile uinputFile
+	keys       map[uint16]bool
+	axes       map[uint16]AxisConfig
+	sent       *sentState
+	reader     *eventReader
+}
+
+// sentState holds the buttons and axis values last sent to the device, so that only changes need to be sent.
+type sentState struct {
+	mutex sync.Mutex
+	// threshold at which the analog triggers press the related buttons (0 if they should not)
+	threshold float32
+	pressed   map[uint16]bool
+	values    map[uint16]int32
+	// directions of the first hat held down using HatPress
+	hatDirections map[HatDirection]bool
+}
+
+func newVGamepad(name []byte, deviceFile uinputFile, id InputID, keys []uint16, axes []AxisConfig, reader *eventReader, options deviceOptions) vGamepad {
+	registered := make(map[uint16]bool, len(keys))
+	for _, key := range keys {
+		registered[key] = true
+	}
+	if options.id != nil {
+		id = *options.id
+	}
+	return vGamepad{
+		name:       name,
+		id:         id,
+		phys:       options.phys,
+		deviceFile: deviceFile,
+		keys:       registered,
+		axes:       axisConfigsByCode(axes),
+		sent: &sentState{
+			threshold:     options.triggerThreshold,
+			pressed:       make(map[uint16]bool),
+			values:        make(map[uint16]int32),
+			hatDirections: make(map[HatDirection]bool)},
+		reader: reader}
 }
 
 // CreateGamepad will create a new gamepad using the given uinput
 // device path of the uinput device.
-func CreateGamepad(path string, name []byte, vendor uint16, product uint16) (Gamepad, error) { // TODO: Consider moving this to a generic function that works for all devices
+func CreateGamepad(path string, name []byte, vendor uint16, product uint16, options ...DeviceOption) (Gamepad, error) { // TODO: Consider moving this to a generic function that works for all devices
 	err := validateDevicePath(path)
 	if err != nil {
 		return nil, err
@@ -90,12 +164,75 @@ func CreateGamepad(path string, name []byte, vendor uint16, product uint16) (Gam
 		return nil, err
 	}
 
-	fd, err := createVGamepadDevice(path, name, vendor, product)
+	axes := defaultGamepadAxes()
+	opts := newGamepadOptions(options)
+	axes, err = addHatAxes(axes, opts.hats)
+	if err != nil {
+		return nil, err
+	}
+	fd, err := createVGamepadDevice(path, name, gamepadID(vendor, product), defaultGamepadKeys, axes, nil, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	return vGamepad{name: name, deviceFile: fd}, nil
+	return newVGamepad(name, fd, gamepadID(vendor, product), defaultGamepadKeys, axes, startEventReader(fd, nil), opts), nil
+}
+
+// CreateGamepadWithAxes will create a new gamepad, using the given configuration for its absolute axes
+// (e.g. to declare triggers with a range of 0 to 255). Axes that are not listed keep their default configuration,
+// meaning a range of -MaximumAxisValue to MaximumAxisValue for sticks, 0 to MaximumAxisValue for triggers and -1 to 1
+// for the hat.
+func CreateGamepadWithAxes(path string, name []byte, vendor uint16, product uint16, axes []AxisConfig, options ...DeviceOption) (Gamepad, error) {
+	err := validateDevicePath(path)
+	if err != nil {
+		return nil, err
+	}
+	err = validateUinputName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	axes = mergeAxisConfigs(defaultGamepadAxes(), axes)
+	opts := newGamepadOptions(options)
+	axes, err = addHatAxes(axes, opts.hats)
+	if err != nil {
+		return nil, err
+	}
+	fd, err := createVGamepadDevice(path, name, gamepadID(vendor, product), defaultGamepadKeys, axes, nil, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return newVGamepad(name, fd, gamepadID(vendor, product), defaultGamepadKeys, axes, startEventReader(fd, nil), opts), nil
+}
+
+// CreateGamepadWithFF will create a new gamepad that advertises force feedback support (rumble, periodic and
+// constant effects). Effects uploaded by applications, as well as requests to play or stop them, are passed
+// on to the given handler.
+func CreateGamepadWithFF(path string, name []byte, vendor uint16, product uint16, handler FFHandler, options ...DeviceOption) (Gamepad, error) {
+	err := validateDevicePath(path)
+	if err != nil {
+		return nil, err
+	}
+	err = validateUinputName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	axes := defaultGamepadAxes()
+	opts := newGamepadOptions(options)
+	axes, err = addHatAxes(axes, opts.hats)
+	if err != nil {
+		return nil, err
+	}
+	fd, err := createVGamepadDevice(path, name, gamepadID(vendor, product), defaultGamepadKeys, axes, defaultFFEffects, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := startEventReader(fd, newForceFeedback(fd, handler))
+
+	return newVGamepad(name, fd, gamepadID(vendor, product), defaultGamepadKeys, axes, reader, opts), nil
 }
 
 func (vg vGamepad) ButtonPress(key int) error {
@@ -111,11 +248,23 @@ func (vg vGamepad) ButtonPress(key int) error {
 }
 
 func (vg vGamepad) ButtonDown(key int) error {
-	return sendBtnEvent(vg.deviceFile, []int{key}, btnStatePressed)
+	return vg.sendButtonEvent(key, btnStatePressed)
 }
 
 func (vg vGamepad) ButtonUp(key int) error {
-	return sendBtnEvent(vg.deviceFile, []int{key}, btnStateReleased)
+	return vg.sendButtonEvent(key, btnStateReleased)
+}
+
+func (vg vGamepad) sendButtonEvent(key int, btnState int) error {
+	vg.sent.mutex.Lock()
+	defer vg.sent.mutex.Unlock()
+
+	err := sendBtnEvent(vg.deviceFile, []int{key}, btnState)
+	if err != nil {
+		return err
+	}
+	vg.sent.pressed[uint16(key)] = btnState == btnStatePressed
+	return nil
 }
 
 func (vg vGamepad) LeftStickMoveX(value float32) error {
@@ -159,10 +308,13 @@ func (vg vGamepad) HatRelease(direction HatDirection) error {
 }
 
 func (vg vGamepad) SendStickAxisEvent(absCode uint16, value float32) error {
+	vg.sent.mutex.Lock()
+	defer vg.sent.mutex.Unlock()
+
 	ev := inputEvent{
 		Type:  evAbs,
 		Code:  absCode,
-		Value: denormalizeInput(value),
+		Value: vg.axisConfig(absCode).denormalize(value),
 	}
 
 	buf, err := inputEventToBuffer(ev)
@@ -174,51 +326,93 @@ func (vg vGamepad) SendStickAxisEvent(absCode uint16, value float32) error {
 	if err != nil {
 		return fmt.Errorf("failed to write abs stick event to device file: %v", err)
 	}
+	vg.sent.values[absCode] = ev.Value
 
 	return syncEvents(vg.deviceFile)
 }
 
+// SendStickEvent sends the values of all given axes within a single frame, ordered by their codes.
 func (vg vGamepad) SendStickEvent(values map[uint16]float32) error {
-	for code, value := range values {
-		ev := inputEvent{
-			Type:  evAbs,
-			Code:  code,
-			Value: denormalizeInput(value),
-		}
+	codes := make([]uint16, 0, len(values))
+	for code := range values {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
 
-		buf, err := inputEventToBuffer(ev)
-		if err != nil {
-			return fmt.Errorf("failed to build abs stick event: %v", err)
-		}
+	vg.sent.mutex.Lock()
+	defer vg.sent.mutex.Unlock()
 
-		_, err = vg.deviceFile.Write(buf)
-		if err != nil {
-			return fmt.Errorf("failed to write abs stick event to device file: %v", err)
-		}
+	if len(codes) == 0 {
+		return syncEvents(vg.deviceFile)
 	}
 
-	return syncEvents(vg.deviceFile)
+	frame := newFrame(vg.deviceFile)
+	absValues := make(map[uint16]int32, len(codes))
+	for _, code := range codes {
+		absValues[code] = vg.axisConfig(code).denormalize(values[code])
+		frame.Emit(evAbs, code, absValues[code])
+	}
+	err := frame.Flush()
+	if err != nil {
+		return fmt.Errorf("failed to write abs stick event to device file: %v", err)
+	}
+	for code, value := range absValues {
+		vg.sent.values[code] = value
+	}
+	return nil
 }
 
-/*
-func (vg vGamepad) SendRawEvent(Type uint16, Code uint16, Value int32) error {
-	buf, err := inputEventToBuffer(inputEvent{
-		Type:  Type,
-		Code:  Code,
-		Value: Value,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to build raw event: [Type=0x%x, Code=0x%x, Value=0x%d]%v", Type, Code, Value, err)
+func (vg vGamepad) LeftTrigger(value float32) error {
+	return vg.sendTriggerEvent(absZ, ButtonTriggerLeft, value)
+}
+
+func (vg vGamepad) RightTrigger(value float32) error {
+	return vg.sendTriggerEvent(absRZ, ButtonTriggerRight, value)
+}
+
+// sendTriggerEvent will move the given trigger axis and, if a threshold was configured, update the related button
+// within the same frame.
+func (vg vGamepad) sendTriggerEvent(absCode uint16, button uint16, value float32) error {
+	axis, ok := vg.axes[absCode]
+	if !ok {
+		return fmt.Errorf("trigger axis %d is not supported by this gamepad", absCode)
 	}
 
-	_, err = vg.deviceFile.Write(buf)
+	vg.sent.mutex.Lock()
+	defer vg.sent.mutex.Unlock()
+
+	frame := newFrame(vg.deviceFile)
+	absValue := denormalizeTrigger(axis, value)
+	frame.Emit(evAbs, absCode, absValue)
+	threshold := vg.sent.threshold
+	pressed := threshold > 0 && value >= threshold
+	if threshold > 0 && pressed != vg.sent.pressed[button] {
+		if pressed {
+			frame.Emit(evKey, button, btnStatePressed)
+		} else {
+			frame.Emit(evKey, button, btnStateReleased)
+		}
+	}
+	err := frame.Flush()
 	if err != nil {
-		return fmt.Errorf("failed to write raw event: [Type=0x%x, Code=0x%x, Value=0x%d]%v", Type, Code, Value, err)
+		return fmt.Errorf("failed to send trigger event: %v", err)
 	}
+	vg.sent.values[absCode] = absValue
+	if threshold > 0 {
+		vg.sent.pressed[button] = pressed
+	}
+	return nil
+}
 
-	return syncEvents(vg.deviceFile)
+// denormalizeTrigger maps a normalized value (0.0:1.0) onto the range of a trigger axis.
+func denormalizeTrigger(axis AxisConfig, value float32) int32 {
+	if value < 0 {
+		value = 0
+	} else if value > 1 {
+		value = 1
+	}
+	return axis.Min + int32(value*float32(axis.Max-axis.Min))
 }
-/**/
 
 func (vg vGamepad) sendHatEvent(direction HatDirection, action HatAction) error {
 	var event uint16
@@ -251,8 +445,17 @@ func (vg vGamepad) sendHatEvent(direction HatDirection, action HatAction) error
 		}
 	}
 
+	vg.sent.mutex.Lock()
+	defer vg.sent.mutex.Unlock()
+
+	// releasing a direction while the opposite direction is still held down moves the hat to the opposite direction
+	vg.sent.hatDirections[direction] = action != Release
 	if action == Release {
-		value = 0
+		if vg.sent.hatDirections[oppositeHatDirections[direction]] {
+			value = -value
+		} else {
+			value = 0
+		}
 	}
 
 	ev := inputEvent{
@@ -270,54 +473,70 @@ func (vg vGamepad) sendHatEvent(direction HatDirection, action HatAction) error
 	if err != nil {
 		return fmt.Errorf("failed to write abs stick event to device file: %v", err)
 	}
+	vg.sent.values[event] = value
 
 	return syncEvents(vg.deviceFile)
 }
 
+// Emit will send a single raw event to the device, immediately followed by a SYN_REPORT.
+func (vg vGamepad) Emit(evType uint16, code uint16, value int32) error {
+	return emitEvent(vg.deviceFile, evType, code, value)
+}
+
+// NewFrame will create an empty frame that may be used to send multiple events at once.
+func (vg vGamepad) NewFrame() *Frame {
+	return newFrame(vg.deviceFile)
+}
+
+// SetEventHandler registers a handler that is invoked for every event sent to the device.
+func (vg vGamepad) SetEventHandler(handler EventHandler) {
+	vg.reader.setHandler(handler)
+}
+
+// ReleaseAll will release all keys, buttons and touch contacts that are currently held down.
+func (vg vGamepad) ReleaseAll() error {
+	vg.sent.mutex.Lock()
+	vg.sent.pressed = make(map[uint16]bool)
+	vg.sent.mutex.Unlock()
+	return releaseAll(vg.deviceFile)
+}
+
 func (vg vGamepad) Close() error {
 	return closeDevice(vg.deviceFile)
 }
 
-func createVGamepadDevice(path string, name []byte, vendor uint16, product uint16) (fd *os.File, err error) {
-	// This array is needed to register the event keys for the gamepad device.
-	keys := []uint16{
-		ButtonGamepad,
+// keys registered by gamepads that are not created from a profile
+var defaultGamepadKeys = []uint16{
+	ButtonGamepad,
 
-		ButtonSouth,
-		ButtonEast,
-		ButtonNorth,
-		ButtonWest,
+	ButtonSouth,
+	ButtonEast,
+	ButtonNorth,
+	ButtonWest,
 
-		ButtonBumperLeft,
-		ButtonBumperRight,
-		ButtonTriggerLeft,
-		ButtonTriggerRight,
-		ButtonThumbLeft,
-		ButtonThumbRight,
+	ButtonBumperLeft,
+	ButtonBumperRight,
+	ButtonTriggerLeft,
+	ButtonTriggerRight,
+	ButtonThumbLeft,
+	ButtonThumbRight,
 
-		ButtonSelect,
-		ButtonStart,
+	ButtonSelect,
+	ButtonStart,
 
-		ButtonDpadUp,    // * * *
-		ButtonDpadDown,  // * These buttons can be used instead of the hat events.
-		ButtonDpadLeft,  // *
-		ButtonDpadRight, // * * *
+	ButtonDpadUp,    // * * *
+	ButtonDpadDown,  // * These buttons can be used instead of the hat events.
+	ButtonDpadLeft,  // *
+	ButtonDpadRight, // * * *
 
-		ButtonMode,
-	}
+	ButtonMode,
+}
 
-	// absEvents is for the absolute events for the gamepad device.
-	absEvents := []uint16{
-		absX,
-		absY,
-		absZ,
-		absRX,
-		absRY,
-		absRZ,
-		absHat0X,
-		absHat0Y,
-	}
+func gamepadID(vendor uint16, product uint16) InputID {
+	return InputID{Bustype: busUsb, Vendor: vendor, Product: product, Version: 1}
+}
 
+func createVGamepadDevice(path string, name []byte, id InputID, keys []uint16, axes []AxisConfig, ffEffects []uint16, options deviceOptions) (fd uinputFile, err error) {
 	deviceFile, err := createDeviceFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create virtual gamepad device: %v", err)
@@ -345,27 +564,93 @@ func createVGamepadDevice(path string, name []byte, vendor uint16, product uint1
 		return nil, fmt.Errorf("failed to register absolute event input device: %v", err)
 	}
 
-	for _, event := range absEvents {
-		err = ioctl(deviceFile, uiSetAbsBit, uintptr(event))
+	for _, axis := range axes {
+		err = ioctl(deviceFile, uiSetAbsBit, uintptr(axis.Code))
+		if err != nil {
+			_ = deviceFile.Close()
+			return nil, fmt.Errorf("failed to register absolute event %v: %v", axis.Code, err)
+		}
+	}
+
+	var effectsMax uint32
+	if len(ffEffects) > 0 {
+		effectsMax = ffEffectsMax
+		err = registerForceFeedback(deviceFile, ffEffects)
 		if err != nil {
 			_ = deviceFile.Close()
-			return nil, fmt.Errorf("failed to register absolute event %v: %v", event, err)
+			return nil, fmt.Errorf("failed to register force feedback: %v", err)
 		}
 	}
 
 	return createUsbDevice(deviceFile,
-		uinputUserDev{
+		uinputSetup{
 			Name: toUinputName(name),
 			ID: inputID{
-				Bustype: busUsb,
-				Vendor:  vendor,
-				Product: product,
-				Version: 1}})
+				Bustype: id.Bustype,
+				Vendor:  id.Vendor,
+				Product: id.Product,
+				Version: id.Version},
+			EffectsMax: effectsMax},
+		toAbsSetups(axes),
+		options)
 }
 
-// Takes in a normalized value (-1.0:1.0) and return an event value
-func denormalizeInput(value float32) int32 {
-	return int32(value * MaximumAxisValue)
+// defaultGamepadAxes returns the configuration of the sticks, triggers and the hat of a gamepad. The triggers range
+// from 0 (released) to MaximumAxisValue.
+func defaultGamepadAxes() []AxisConfig {
+	var axes []AxisConfig
+	for _, code := range []uint16{absX, absY, absZ, absRX, absRY, absRZ, absHat0X, absHat0Y} {
+		if code == absZ || code == absRZ {
+			axes = append(axes, AxisConfig{Code: code, Min: 0, Max: MaximumAxisValue})
+			continue
+		}
+		axes = append(axes, defaultAxisConfig(code))
+	}
+	return axes
+}
+
+// defaultAxisConfig returns the configuration used for axes that were registered without explicit configuration.
+// Hats are digital and range from -1 to 1, all other axes use -MaximumAxisValue to MaximumAxisValue.
+func defaultAxisConfig(code uint16) AxisConfig {
+	if code >= AbsHat0X && code <= AbsHat3Y {
+		return AxisConfig{Code: code, Min: -1, Max: 1}
+	}
+	return AxisConfig{Code: code, Min: -MaximumAxisValue, Max: MaximumAxisValue}
+}
+
+// mergeAxisConfigs replaces the default configuration of each axis found in overrides. Axes that are not part of the
+// defaults are appended.
+func mergeAxisConfigs(defaults []AxisConfig, overrides []AxisConfig) []AxisConfig {
+	axes := append([]AxisConfig(nil), defaults...)
+	for _, override := range overrides {
+		replaced := false
+		for i := range axes {
+			if axes[i].Code == override.Code {
+				axes[i] = override
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			axes = append(axes, override)
+		}
+	}
+	return axes
+}
+
+func axisConfigsByCode(axes []AxisConfig) map[uint16]AxisConfig {
+	configs := make(map[uint16]AxisConfig, len(axes))
+	for _, axis := range axes {
+		configs[axis.Code] = axis
+	}
+	return configs
+}
+
+func (vg vGamepad) axisConfig(code uint16) AxisConfig {
+	if axis, ok := vg.axes[code]; ok {
+		return axis
+	}
+	return defaultAxisConfig(code)
 }
 
 func (vg vGamepad) FetchSyspath() (string, error) {
@@ -246,3 +246,208 @@ func TestGamepadMoveToFailsOnClosedDevice(t *testing.T) {
 		t.Fatalf("Expected error due to closed device, but no error was returned.")
 	}
 }
+
+func TestAxisMovementWithCustomRanges(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	vg, err := CreateGamepadWithAxes(fake.Path(), []byte("Hot gophers in your area"), 0xDEAD, 0xBEEF, []AxisConfig{
+		{Code: AbsX, Min: -32768, Max: 32767, Fuzz: 16, Flat: 128},
+		{Code: AbsZ, Min: 0, Max: 255},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+	defer vg.Close()
+
+	for _, value := range []float32{-1, 0.5, 1} {
+		err = vg.LeftStickMoveX(value)
+		if err != nil {
+			t.Fatalf("Failed to send axis event. Last error was: %s\n", err)
+		}
+	}
+	for _, value := range []float32{-1, 0, 1} {
+		err = vg.SendStickAxisEvent(AbsZ, value)
+		if err != nil {
+			t.Fatalf("Failed to send axis event. Last error was: %s\n", err)
+		}
+	}
+
+	fake.Device("Hot gophers in your area").ExpectEvents(t,
+		Event{Type: EvAbs, Code: AbsX, Value: -32768},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvAbs, Code: AbsX, Value: 16383},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvAbs, Code: AbsX, Value: 32767},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvAbs, Code: AbsZ, Value: 0},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvAbs, Code: AbsZ, Value: 127},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvAbs, Code: AbsZ, Value: 255},
+		Event{Type: EvSyn, Code: SynReport})
+}
+
+func TestGamepadAxisConfigsOverrideDefaults(t *testing.T) {
+	axes := mergeAxisConfigs(defaultGamepadAxes(), []AxisConfig{
+		{Code: AbsZ, Min: 0, Max: 255},
+		{Code: AbsThrottle, Min: 0, Max: 1023},
+	})
+
+	configs := axisConfigsByCode(axes)
+	if len(configs) != len(axes) || len(axes) != 9 {
+		t.Fatalf("Expected 9 distinct axes, but got %d", len(axes))
+	}
+	if configs[AbsZ].Max != 255 || configs[AbsThrottle].Max != 1023 {
+		t.Fatalf("Expected axis configuration to be overridden, but got %+v", configs)
+	}
+	if configs[AbsX].Max != MaximumAxisValue || configs[AbsHat0X].Max != 1 {
+		t.Fatalf("Expected default axis configuration to be kept, but got %+v", configs)
+	}
+}
+
+func TestTriggersAreMappedOntoAxisRange(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	vg, err := CreateGamepadWithAxes(fake.Path(), []byte("Test Gamepad"), 0xDEAD, 0xBEEF, []AxisConfig{
+		{Code: AbsZ, Min: 0, Max: 255},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+	defer vg.Close()
+
+	for _, value := range []float32{0, 0.5, 1, 2} {
+		err = vg.LeftTrigger(value)
+		if err != nil {
+			t.Fatalf("Failed to pull the left trigger. Last error was: %s\n", err)
+		}
+	}
+	fake.Device("Test Gamepad").ExpectEvents(t,
+		Event{Type: EvAbs, Code: AbsZ, Value: 0},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvAbs, Code: AbsZ, Value: 127},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvAbs, Code: AbsZ, Value: 255},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvAbs, Code: AbsZ, Value: 255},
+		Event{Type: EvSyn, Code: SynReport})
+}
+
+func TestDefaultTriggersRangeFromZero(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	vg, err := CreateGamepad(fake.Path(), []byte("Test Gamepad"), 0xDEAD, 0xBEEF)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+	defer vg.Close()
+	dev := fake.Device("Test Gamepad")
+
+	for _, code := range []uint16{AbsZ, AbsRZ} {
+		axis, _ := dev.Axis(code)
+		if axis.Min != 0 || axis.Max != MaximumAxisValue {
+			t.Fatalf("Expected trigger axis %d to range from 0 to %d, but got %+v", code, MaximumAxisValue, axis)
+		}
+	}
+
+	for _, value := range []float32{1, 0} {
+		err = vg.LeftTrigger(value)
+		if err != nil {
+			t.Fatalf("Failed to pull the left trigger. Last error was: %s\n", err)
+		}
+		err = vg.RightTrigger(value)
+		if err != nil {
+			t.Fatalf("Failed to pull the right trigger. Last error was: %s\n", err)
+		}
+	}
+	dev.ExpectEvents(t,
+		Event{Type: EvAbs, Code: AbsZ, Value: MaximumAxisValue},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvAbs, Code: AbsRZ, Value: MaximumAxisValue},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvAbs, Code: AbsZ, Value: 0},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvAbs, Code: AbsRZ, Value: 0},
+		Event{Type: EvSyn, Code: SynReport})
+}
+
+func TestGenericGamepadKeepsSymmetricAxisZ(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	vg, err := CreateGenericGamepad(fake.Path(), BusUsb, []byte("Test Generic Gamepad"), 0xDEAD, 0xBEEF, 1,
+		[]uint16{ButtonSouth}, []uint16{AbsX, AbsZ})
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+	defer vg.Close()
+	dev := fake.Device("Test Generic Gamepad")
+
+	axis, _ := dev.Axis(AbsZ)
+	if axis.Min != -MaximumAxisValue || axis.Max != MaximumAxisValue {
+		t.Fatalf("Expected axis %d to range from %d to %d, but got %+v", AbsZ, -MaximumAxisValue, MaximumAxisValue, axis)
+	}
+
+	for _, value := range []float32{0, -1, 1} {
+		err = vg.SendStickAxisEvent(AbsZ, value)
+		if err != nil {
+			t.Fatalf("Failed to move the axis. Last error was: %s\n", err)
+		}
+	}
+	dev.ExpectEvents(t,
+		Event{Type: EvAbs, Code: AbsZ, Value: 0},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvAbs, Code: AbsZ, Value: -MaximumAxisValue},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvAbs, Code: AbsZ, Value: MaximumAxisValue},
+		Event{Type: EvSyn, Code: SynReport})
+}
+
+func TestTriggersPressButtonsAtThreshold(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	vg, err := CreateGamepadFromProfile(fake.Path(), ProfileDualShock4, nil, WithTriggerThreshold(0.5))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+	defer vg.Close()
+	dev := fake.Device(ProfileDualShock4.Name)
+
+	for _, value := range []float32{0.25, 0.5, 1, 0.25} {
+		err = vg.RightTrigger(value)
+		if err != nil {
+			t.Fatalf("Failed to pull the right trigger. Last error was: %s\n", err)
+		}
+	}
+	dev.ExpectEvents(t,
+		Event{Type: EvAbs, Code: AbsRZ, Value: 63},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvAbs, Code: AbsRZ, Value: 127},
+		Event{Type: EvKey, Code: ButtonTriggerRight, Value: 1},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvAbs, Code: AbsRZ, Value: 255},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvAbs, Code: AbsRZ, Value: 63},
+		Event{Type: EvKey, Code: ButtonTriggerRight, Value: 0},
+		Event{Type: EvSyn, Code: SynReport})
+}
+
+func TestTriggersFailWithoutTriggerAxes(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	vg, err := CreateGamepadFromProfile(fake.Path(), ProfileSwitchPro, nil)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+	defer vg.Close()
+
+	err = vg.LeftTrigger(1)
+	if err == nil {
+		t.Fatalf("Expected the left trigger to fail, since the gamepad has no trigger axes")
+	}
+}
@@ -0,0 +1,177 @@
+package uinput
+
+import "fmt"
+
+// GamepadButtons is a set of gamepad buttons (similar to the buttons of an XInput state), as used by GamepadState.
+type GamepadButtons uint32
+
+const (
+	GamepadButtonSouth GamepadButtons = 1 << iota
+	GamepadButtonEast
+	GamepadButtonNorth
+	GamepadButtonWest
+	GamepadButtonBumperLeft
+	GamepadButtonBumperRight
+	GamepadButtonTriggerLeft
+	GamepadButtonTriggerRight
+	GamepadButtonThumbLeft
+	GamepadButtonThumbRight
+	GamepadButtonSelect
+	GamepadButtonStart
+	GamepadButtonMode
+	GamepadButtonDpadUp
+	GamepadButtonDpadDown
+	GamepadButtonDpadLeft
+	GamepadButtonDpadRight
+)
+
+// codes of the buttons of a GamepadState, in the order they are sent
+var gamepadButtonCodes = []struct {
+	button GamepadButtons
+	code   uint16
+}{
+	{GamepadButtonSouth, ButtonSouth},
+	{GamepadButtonEast, ButtonEast},
+	{GamepadButtonNorth, ButtonNorth},
+	{GamepadButtonWest, ButtonWest},
+	{GamepadButtonBumperLeft, ButtonBumperLeft},
+	{GamepadButtonBumperRight, ButtonBumperRight},
+	{GamepadButtonTriggerLeft, ButtonTriggerLeft},
+	{GamepadButtonTriggerRight, ButtonTriggerRight},
+	{GamepadButtonThumbLeft, ButtonThumbLeft},
+	{GamepadButtonThumbRight, ButtonThumbRight},
+	{GamepadButtonSelect, ButtonSelect},
+	{GamepadButtonStart, ButtonStart},
+	{GamepadButtonMode, ButtonMode},
+	{GamepadButtonDpadUp, ButtonDpadUp},
+	{GamepadButtonDpadDown, ButtonDpadDown},
+	{GamepadButtonDpadLeft, ButtonDpadLeft},
+	{GamepadButtonDpadRight, ButtonDpadRight},
+}
+
+// A GamepadState is a snapshot of all controls of a gamepad, as passed to SetState.
+type GamepadState struct {
+	Buttons GamepadButtons
+	// position of the sticks (-1.0:1.0)
+	LeftStickX  float32
+	LeftStickY  float32
+	RightStickX float32
+	RightStickY float32
+	// position of the analog triggers (0.0:1.0)
+	LeftTrigger  float32
+	RightTrigger float32
+	// position of the hat along the x-axis (-1 left, 1 right) and y-axis (-1 up, 1 down). Gamepads that report the d-pad
+	// using the hat (like those created from a profile) move the hat according to the d-pad buttons, unless its
+	// position is given here.
+	HatX int32
+	HatY int32
+}
+
+// SetState compares the given state to the state last sent to the device and sends the changes within a single frame:
+// Buttons come first, followed by the sticks, the triggers and the hat. Buttons the gamepad did not register are
+// ignored. Changes made using the other methods of the gamepad are taken into account, with the exception of raw
+// events (see Emit and NewFrame).
+func (vg vGamepad) SetState(state GamepadState) error {
+	vg.sent.mutex.Lock()
+	defer vg.sent.mutex.Unlock()
+
+	threshold := vg.sent.threshold
+	pressed := make(map[uint16]bool, len(gamepadButtonCodes))
+	for _, b := range gamepadButtonCodes {
+		if vg.keys[b.code] {
+			pressed[b.code] = state.Buttons&b.button != 0
+		}
+	}
+	if threshold > 0 {
+		if vg.keys[ButtonTriggerLeft] {
+			pressed[ButtonTriggerLeft] = pressed[ButtonTriggerLeft] || state.LeftTrigger >= threshold
+		}
+		if vg.keys[ButtonTriggerRight] {
+			pressed[ButtonTriggerRight] = pressed[ButtonTriggerRight] || state.RightTrigger >= threshold
+		}
+	}
+	if state.HatX == 0 && !vg.keys[ButtonDpadLeft] && !vg.keys[ButtonDpadRight] {
+		state.HatX = dpadAxisValue(state.Buttons, GamepadButtonDpadLeft, GamepadButtonDpadRight)
+	}
+	if state.HatY == 0 && !vg.keys[ButtonDpadUp] && !vg.keys[ButtonDpadDown] {
+		state.HatY = dpadAxisValue(state.Buttons, GamepadButtonDpadUp, GamepadButtonDpadDown)
+	}
+
+	values := make(map[uint16]int32)
+	axes := []struct {
+		code  uint16
+		value float32
+	}{
+		{absX, state.LeftStickX},
+		{absY, state.LeftStickY},
+		{absRX, state.RightStickX},
+		{absRY, state.RightStickY},
+		{absZ, state.LeftTrigger},
+		{absRZ, state.RightTrigger},
+		{absHat0X, float32(state.HatX)},
+		{absHat0Y, float32(state.HatY)},
+	}
+	frame := newFrame(vg.deviceFile)
+	for _, b := range gamepadButtonCodes {
+		if !vg.keys[b.code] {
+			continue
+		}
+		if pressed[b.code] != vg.sent.pressed[b.code] {
+			if pressed[b.code] {
+				frame.Emit(evKey, b.code, btnStatePressed)
+			} else {
+				frame.Emit(evKey, b.code, btnStateReleased)
+			}
+		}
+	}
+	for _, a := range axes {
+		axis, ok := vg.axes[a.code]
+		if !ok {
+			// the gamepad lacks this axis (e.g. a profile with digital triggers)
+			continue
+		}
+		var value int32
+		switch a.code {
+		case absZ, absRZ:
+			value = denormalizeTrigger(axis, a.value)
+		case absHat0X, absHat0Y:
+			value = int32(a.value)
+			if value < -1 {
+				value = -1
+			} else if value > 1 {
+				value = 1
+			}
+		default:
+			value = axis.denormalize(a.value)
+		}
+		if sent, ok := vg.sent.values[a.code]; !ok || sent != value {
+			frame.Emit(evAbs, a.code, value)
+			values[a.code] = value
+		}
+	}
+
+	err := frame.Flush()
+	if err != nil {
+		return fmt.Errorf("failed to send gamepad state: %v", err)
+	}
+	for code, p := range pressed {
+		vg.sent.pressed[code] = p
+	}
+	for code, value := range values {
+		vg.sent.values[code] = value
+	}
+	vg.sent.setHatDirections(vg.sent.values[absHat0X], vg.sent.values[absHat0Y])
+	return nil
+}
+
+// dpadAxisValue maps two opposite d-pad buttons onto a hat axis (-1, 0 or 1).
+func dpadAxisValue(buttons GamepadButtons, negative GamepadButtons, positive GamepadButtons) int32 {
+	var value int32
+	if buttons&negative != 0 {
+		value--
+	}
+	if buttons&positive != 0 {
+		value++
+	}
+	return value
+}
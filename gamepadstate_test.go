@@ -0,0 +1,152 @@
+package uinput
+
+import "testing"
+
+func TestSetStateOnlySendsChanges(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	vg, err := CreateGamepadFromProfile(fake.Path(), ProfileXbox360, nil)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+	defer vg.Close()
+	dev := fake.Device(ProfileXbox360.Name)
+
+	err = vg.SetState(GamepadState{Buttons: GamepadButtonSouth | GamepadButtonStart, LeftStickX: 1, HatY: -1})
+	if err != nil {
+		t.Fatalf("Failed to set the gamepad state. Last error was: %s\n", err)
+	}
+	dev.ExpectEvents(t,
+		Event{Type: EvKey, Code: ButtonSouth, Value: 1},
+		Event{Type: EvKey, Code: ButtonStart, Value: 1},
+		Event{Type: EvAbs, Code: AbsX, Value: 32767},
+		Event{Type: EvAbs, Code: AbsY, Value: 0},
+		Event{Type: EvAbs, Code: AbsRX, Value: 0},
+		Event{Type: EvAbs, Code: AbsRY, Value: 0},
+		Event{Type: EvAbs, Code: AbsZ, Value: 0},
+		Event{Type: EvAbs, Code: AbsRZ, Value: 0},
+		Event{Type: EvAbs, Code: AbsHat0X, Value: 0},
+		Event{Type: EvAbs, Code: AbsHat0Y, Value: -1},
+		Event{Type: EvSyn, Code: SynReport})
+
+	err = vg.SetState(GamepadState{Buttons: GamepadButtonSouth | GamepadButtonStart, LeftStickX: 1, HatY: -1})
+	if err != nil {
+		t.Fatalf("Failed to set the gamepad state. Last error was: %s\n", err)
+	}
+	dev.ExpectNoEvents(t)
+
+	err = vg.SetState(GamepadState{Buttons: GamepadButtonSouth, LeftStickX: 1, RightTrigger: 1})
+	if err != nil {
+		t.Fatalf("Failed to set the gamepad state. Last error was: %s\n", err)
+	}
+	dev.ExpectEvents(t,
+		Event{Type: EvKey, Code: ButtonStart, Value: 0},
+		Event{Type: EvAbs, Code: AbsRZ, Value: 255},
+		Event{Type: EvAbs, Code: AbsHat0Y, Value: 0},
+		Event{Type: EvSyn, Code: SynReport})
+}
+
+func TestSetStateRespectsOtherMethods(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	vg, err := CreateGamepad(fake.Path(), []byte("Test Gamepad"), 0xDEAD, 0xBEEF, WithTriggerThreshold(0.5))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+	defer vg.Close()
+	dev := fake.Device("Test Gamepad")
+
+	err = vg.SetState(GamepadState{})
+	if err != nil {
+		t.Fatalf("Failed to set the gamepad state. Last error was: %s\n", err)
+	}
+	dev.ClearEvents()
+
+	err = vg.ButtonDown(ButtonNorth)
+	if err != nil {
+		t.Fatalf("Failed to press the button. Last error was: %s\n", err)
+	}
+	err = vg.LeftStickMove(-1, 0.5)
+	if err != nil {
+		t.Fatalf("Failed to move the left stick. Last error was: %s\n", err)
+	}
+	dev.ClearEvents()
+
+	// the button is already held down and the stick already in place, only the trigger changes
+	err = vg.SetState(GamepadState{Buttons: GamepadButtonNorth, LeftStickX: -1, LeftStickY: 0.5, LeftTrigger: 0.75})
+	if err != nil {
+		t.Fatalf("Failed to set the gamepad state. Last error was: %s\n", err)
+	}
+	dev.ExpectEvents(t,
+		Event{Type: EvKey, Code: ButtonTriggerLeft, Value: 1},
+		Event{Type: EvAbs, Code: AbsZ, Value: 24575},
+		Event{Type: EvSyn, Code: SynReport})
+}
+
+func TestStickEventsAreSentInOrder(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	vg, err := CreateGamepad(fake.Path(), []byte("Test Gamepad"), 0xDEAD, 0xBEEF)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+	defer vg.Close()
+
+	for i := 0; i < 10; i++ {
+		err = vg.SendStickEvent(map[uint16]float32{AbsRY: -1, AbsX: 1, AbsRX: 0, AbsY: -1})
+		if err != nil {
+			t.Fatalf("Failed to move the sticks. Last error was: %s\n", err)
+		}
+		fake.Device("Test Gamepad").ExpectEvents(t,
+			Event{Type: EvAbs, Code: AbsX, Value: MaximumAxisValue},
+			Event{Type: EvAbs, Code: AbsY, Value: -MaximumAxisValue},
+			Event{Type: EvAbs, Code: AbsRX, Value: 0},
+			Event{Type: EvAbs, Code: AbsRY, Value: -MaximumAxisValue},
+			Event{Type: EvSyn, Code: SynReport})
+	}
+}
+
+func TestSetStateSkipsUnregisteredButtons(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	// xpad reports the d-pad using the hat and has no digital trigger buttons
+	vg, err := CreateGamepadFromProfile(fake.Path(), ProfileXbox360, nil, WithTriggerThreshold(0.5))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+	defer vg.Close()
+	dev := fake.Device(ProfileXbox360.Name)
+
+	err = vg.SetState(GamepadState{})
+	if err != nil {
+		t.Fatalf("Failed to set the gamepad state. Last error was: %s\n", err)
+	}
+	dev.ClearEvents()
+
+	err = vg.SetState(GamepadState{
+		Buttons:     GamepadButtonSouth | GamepadButtonTriggerLeft | GamepadButtonDpadUp | GamepadButtonDpadLeft,
+		LeftTrigger: 1,
+	})
+	if err != nil {
+		t.Fatalf("Failed to set the gamepad state. Last error was: %s\n", err)
+	}
+	dev.ExpectEvents(t,
+		Event{Type: EvKey, Code: ButtonSouth, Value: 1},
+		Event{Type: EvAbs, Code: AbsZ, Value: 255},
+		Event{Type: EvAbs, Code: AbsHat0X, Value: -1},
+		Event{Type: EvAbs, Code: AbsHat0Y, Value: -1},
+		Event{Type: EvSyn, Code: SynReport})
+
+	err = vg.SetState(GamepadState{Buttons: GamepadButtonSouth | GamepadButtonDpadUp})
+	if err != nil {
+		t.Fatalf("Failed to set the gamepad state. Last error was: %s\n", err)
+	}
+	dev.ExpectEvents(t,
+		Event{Type: EvAbs, Code: AbsZ, Value: 0},
+		Event{Type: EvAbs, Code: AbsHat0X, Value: 0},
+		Event{Type: EvSyn, Code: SynReport})
+}
@@ -2,12 +2,11 @@ package uinput
 
 import (
 	"fmt"
-	"os"
 )
 
 // CreateGamepad will create a new gamepad using the given uinput
 // device path of the uinput device.
-func CreateGenericGamepad(path string, bustype uint16, name []byte, vendor uint16, product uint16, version uint16, keys []uint16, absEvents []uint16) (Gamepad, error) {
+func CreateGenericGamepad(path string, bustype uint16, name []byte, vendor uint16, product uint16, version uint16, keys []uint16, absEvents []uint16, options ...DeviceOption) (Gamepad, error) {
 	err := validateDevicePath(path)
 	if err != nil {
 		return nil, err
@@ -17,15 +16,52 @@ func CreateGenericGamepad(path string, bustype uint16, name []byte, vendor uint1
 		return nil, err
 	}
 
-	fd, err := createVGenericGamepadDevice(path, bustype, name, vendor, product, version, keys, absEvents)
+	var axes []AxisConfig
+	for _, code := range absEvents {
+		axes = append(axes, defaultAxisConfig(code))
+	}
+
+	opts := newGamepadOptions(options)
+	axes, err = addHatAxes(axes, opts.hats)
+	if err != nil {
+		return nil, err
+	}
+	fd, err := createVGenericGamepadDevice(path, bustype, name, vendor, product, version, keys, axes, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	id := InputID{Bustype: bustype, Vendor: vendor, Product: product, Version: version}
+	return newVGamepad(name, fd, id, keys, axes, startEventReader(fd, nil), opts), nil
+}
+
+// CreateGenericGamepadWithAxes will create a new gamepad with the given keys and absolute axes. Unlike
+// CreateGenericGamepad, the range, fuzz, flat and resolution of each axis are taken from the given configuration.
+func CreateGenericGamepadWithAxes(path string, bustype uint16, name []byte, vendor uint16, product uint16, version uint16, keys []uint16, axes []AxisConfig, options ...DeviceOption) (Gamepad, error) {
+	err := validateDevicePath(path)
+	if err != nil {
+		return nil, err
+	}
+	err = validateUinputName(name)
 	if err != nil {
 		return nil, err
 	}
 
-	return vGamepad{name: name, deviceFile: fd}, nil
+	opts := newGamepadOptions(options)
+	axes, err = addHatAxes(axes, opts.hats)
+	if err != nil {
+		return nil, err
+	}
+	fd, err := createVGenericGamepadDevice(path, bustype, name, vendor, product, version, keys, axes, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	id := InputID{Bustype: bustype, Vendor: vendor, Product: product, Version: version}
+	return newVGamepad(name, fd, id, keys, axes, startEventReader(fd, nil), opts), nil
 }
 
-func createVGenericGamepadDevice(path string, bustype uint16, name []byte, vendor uint16, product uint16, version uint16, keys []uint16, absEvents []uint16) (fd *os.File, err error) {
+func createVGenericGamepadDevice(path string, bustype uint16, name []byte, vendor uint16, product uint16, version uint16, keys []uint16, axes []AxisConfig, options deviceOptions) (fd uinputFile, err error) {
 	deviceFile, err := createDeviceFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create virtual gamepad device: %v", err)
@@ -39,7 +75,6 @@ func createVGenericGamepadDevice(path string, bustype uint16, name []byte, vendo
 	}
 
 	for _, code := range keys {
-		fmt.Printf("registering uiSetKeyBit 0x%x\n", code)
 		err = ioctl(deviceFile, uiSetKeyBit, uintptr(code))
 		if err != nil {
 			_ = deviceFile.Close()
@@ -54,41 +89,22 @@ func createVGenericGamepadDevice(path string, bustype uint16, name []byte, vendo
 		return nil, fmt.Errorf("failed to register absolute event input device: %v", err)
 	}
 
-	for _, event := range absEvents {
-		fmt.Printf("registering uiSetAbsBit 0x%x\n", event)
-		err = ioctl(deviceFile, uiSetAbsBit, uintptr(event))
+	for _, axis := range axes {
+		err = ioctl(deviceFile, uiSetAbsBit, uintptr(axis.Code))
 		if err != nil {
 			_ = deviceFile.Close()
-			return nil, fmt.Errorf("failed to register absolute event %v: %v", event, err)
+			return nil, fmt.Errorf("failed to register absolute event %v: %v", axis.Code, err)
 		}
 	}
 
-	/*
-		// misc event
-		if len(mscEvents) > 0 {
-			err = registerDevice(deviceFile, uintptr(evMsc))
-			if err != nil {
-				_ = deviceFile.Close()
-				return nil, fmt.Errorf("failed to register misc event input device: %v", err)
-			}
-
-			for _, event := range mscEvents {
-				fmt.Printf("registering uiSetMscBit 0x%x\n", event)
-				err = ioctl(deviceFile, uiSetMscBit, uintptr(event))
-				if err != nil {
-					_ = deviceFile.Close()
-					return nil, fmt.Errorf("failed to register misc event %v: %v", event, err)
-				}
-			}
-		}
-		/**/
-
 	return createUsbDevice(deviceFile,
-		uinputUserDev{
+		uinputSetup{
 			Name: toUinputName(name),
 			ID: inputID{
 				Bustype: bustype,
 				Vendor:  vendor,
 				Product: product,
-				Version: version}})
+				Version: version}},
+		toAbsSetups(axes),
+		options)
 }
@@ -0,0 +1,199 @@
+package uinput
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// default timing of gestures
+const (
+	defaultGestureDuration   = 300 * time.Millisecond
+	defaultTapDuration       = 50 * time.Millisecond
+	defaultLongPressDuration = time.Second
+	defaultGestureStepRate   = 60
+)
+
+// GestureOptions control the timing and shape of a gesture. The zero value selects sensible defaults.
+type GestureOptions struct {
+	// Duration of the gesture (for taps and long presses, the time the fingers are held down).
+	Duration time.Duration
+	// StepRate is the number of steps (frames) per second sent while the fingers move. Defaults to 60.
+	StepRate int
+	// FingerSpacing is the distance between adjacent fingers of swipes and taps. Defaults to 1/20 of the x-axis.
+	FingerSpacing int32
+}
+
+type gesturePoint struct {
+	x float64
+	y float64
+}
+
+// touchSurface holds the contacts of a multi touch device along with the bounds of its surface. It implements the
+// gestures shared by all multi touch devices.
+type touchSurface struct {
+	contacts *contactState
+	minX     int32
+	maxX     int32
+	minY     int32
+	maxY     int32
+}
+
+// A gesturePath returns the positions of all fingers at the given progress of the gesture (from 0 to 1).
+type gesturePath func(progress float64) []gesturePoint
+
+// Pinch will put two fingers onto the surface, horizontally aligned around the given center, and move them until
+// they are endDistance apart. A pinch with endDistance > startDistance zooms in, otherwise it zooms out.
+func (surface touchSurface) Pinch(centerX int32, centerY int32, startDistance int32, endDistance int32, options GestureOptions) error {
+	if startDistance < 0 || endDistance < 0 {
+		return fmt.Errorf("failed to perform pinch. Distances must not be negative")
+	}
+	path := func(progress float64) []gesturePoint {
+		half := (float64(startDistance) + float64(endDistance-startDistance)*progress) / 2
+		return []gesturePoint{
+			{float64(centerX) - half, float64(centerY)},
+			{float64(centerX) + half, float64(centerY)},
+		}
+	}
+	return surface.performGesture(path, 2, options.withDuration(defaultGestureDuration))
+}
+
+// Rotate will put two fingers onto the surface, opposite of each other on a circle with the given radius around the
+// center, and rotate them by the given angle (in degrees). Since the y-axis points down, positive angles rotate
+// clockwise.
+func (surface touchSurface) Rotate(centerX int32, centerY int32, radius int32, degrees float64, options GestureOptions) error {
+	if radius <= 0 {
+		return fmt.Errorf("failed to perform rotation. Radius must be positive")
+	}
+	path := func(progress float64) []gesturePoint {
+		angle := degrees * progress * math.Pi / 180
+		dx, dy := float64(radius)*math.Cos(angle), float64(radius)*math.Sin(angle)
+		return []gesturePoint{
+			{float64(centerX) + dx, float64(centerY) + dy},
+			{float64(centerX) - dx, float64(centerY) - dy},
+		}
+	}
+	return surface.performGesture(path, 2, options.withDuration(defaultGestureDuration))
+}
+
+// Swipe will put the given number of fingers onto the surface (horizontally aligned around the start position) and
+// move them to the end position.
+func (surface touchSurface) Swipe(fingers int, fromX int32, fromY int32, toX int32, toY int32, options GestureOptions) error {
+	spacing := surface.fingerSpacing(options)
+	path := func(progress float64) []gesturePoint {
+		x := float64(fromX) + float64(toX-fromX)*progress
+		y := float64(fromY) + float64(toY-fromY)*progress
+		return alignFingers(fingers, x, y, spacing)
+	}
+	return surface.performGesture(path, fingers, options.withDuration(defaultGestureDuration))
+}
+
+// Tap will tap the surface using the given number of fingers (horizontally aligned around the position).
+func (surface touchSurface) Tap(fingers int, x int32, y int32, options GestureOptions) error {
+	spacing := surface.fingerSpacing(options)
+	path := func(progress float64) []gesturePoint {
+		return alignFingers(fingers, float64(x), float64(y), spacing)
+	}
+	return surface.performGesture(path, fingers, options.withDuration(defaultTapDuration))
+}
+
+// LongPress will put a single finger onto the surface and hold it there for the duration of the gesture (one second
+// by default).
+func (surface touchSurface) LongPress(x int32, y int32, options GestureOptions) error {
+	path := func(progress float64) []gesturePoint {
+		return []gesturePoint{{float64(x), float64(y)}}
+	}
+	return surface.performGesture(path, 1, options.withDuration(defaultLongPressDuration))
+}
+
+func (o GestureOptions) withDuration(duration time.Duration) GestureOptions {
+	if o.Duration == 0 {
+		o.Duration = duration
+	}
+	if o.StepRate == 0 {
+		o.StepRate = defaultGestureStepRate
+	}
+	return o
+}
+
+func (surface touchSurface) fingerSpacing(options GestureOptions) float64 {
+	if options.FingerSpacing > 0 {
+		return float64(options.FingerSpacing)
+	}
+	return float64(surface.maxX-surface.minX) / 20
+}
+
+// alignFingers will place the fingers next to each other, centered around the given position.
+func alignFingers(fingers int, x float64, y float64, spacing float64) []gesturePoint {
+	points := make([]gesturePoint, fingers)
+	for i := range points {
+		points[i] = gesturePoint{x + (float64(i)-float64(fingers-1)/2)*spacing, y}
+	}
+	return points
+}
+
+// performGesture will put the fingers down at the start of the path, move them along the path (sending all fingers
+// in a single frame per step) and lift them at the end of the path. Only contacts that are not down are used.
+func (surface touchSurface) performGesture(path gesturePath, fingers int, options GestureOptions) error {
+	if options.Duration < 0 || options.StepRate < 0 {
+		return fmt.Errorf("failed to perform gesture. Duration and step rate must not be negative")
+	}
+
+	var contacts []*Contact
+	for _, c := range surface.contacts.all() {
+		if len(contacts) < fingers && !c.IsDown() {
+			contacts = append(contacts, c)
+		}
+	}
+	if fingers < 1 || len(contacts) < fingers {
+		return fmt.Errorf("failed to perform gesture. %d fingers requested, but only %d contacts are available",
+			fingers, len(contacts))
+	}
+
+	err := surface.contacts.apply(surface.gestureUpdates(contacts, path(0), contactDown)...)
+	if err != nil {
+		return fmt.Errorf("failed to perform gesture: %v", err)
+	}
+
+	steps := int(options.Duration.Seconds() * float64(options.StepRate))
+	if steps < 1 {
+		steps = 1
+	}
+	interval := options.Duration / time.Duration(steps)
+	for i := 1; i <= steps; i++ {
+		time.Sleep(interval)
+		err = surface.contacts.apply(surface.gestureUpdates(contacts, path(float64(i)/float64(steps)), contactMove)...)
+		if err != nil {
+			_ = surface.contacts.apply(surface.gestureUpdates(contacts, nil, contactUp)...)
+			return fmt.Errorf("failed to perform gesture: %v", err)
+		}
+	}
+
+	err = surface.contacts.apply(surface.gestureUpdates(contacts, nil, contactUp)...)
+	if err != nil {
+		return fmt.Errorf("failed to perform gesture: %v", err)
+	}
+	return nil
+}
+
+func (surface touchSurface) gestureUpdates(contacts []*Contact, points []gesturePoint, action contactAction) []contactUpdate {
+	updates := make([]contactUpdate, len(contacts))
+	for i, c := range contacts {
+		updates[i] = contactUpdate{contact: c, action: action}
+		if points != nil {
+			updates[i].x = clamp(int32(math.Round(points[i].x)), surface.minX, surface.maxX)
+			updates[i].y = clamp(int32(math.Round(points[i].y)), surface.minY, surface.maxY)
+		}
+	}
+	return updates
+}
+
+func clamp(value int32, min int32, max int32) int32 {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
@@ -0,0 +1,201 @@
+package uinput
+
+import (
+	"testing"
+	"time"
+)
+
+// splitFrames will split the recorded events at each SYN_REPORT.
+func splitFrames(events []Event) [][]Event {
+	var frames [][]Event
+	var frame []Event
+	for _, ev := range events {
+		if ev.Type == EvSyn {
+			frames = append(frames, frame)
+			frame = nil
+			continue
+		}
+		frame = append(frame, ev)
+	}
+	return frames
+}
+
+func TestPinchMovesBothFingersPerFrame(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+	dev, err := CreateMultiTouch(fake.Path(), []byte("Test MultiTouch"), 0, 1000, 0, 500, 5)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual multi touch device. Last error was: %s\n", err)
+	}
+	fd := fake.Device("Test MultiTouch")
+	defer dev.Close()
+
+	err = dev.Pinch(500, 250, 100, 300, GestureOptions{Duration: 20 * time.Millisecond, StepRate: 100})
+	if err != nil {
+		t.Fatalf("Failed to pinch. Last error was: %s\n", err)
+	}
+
+	frames := splitFrames(fd.Events())
+	if len(frames) != 4 {
+		t.Fatalf("Expected 4 frames (down, 2 steps, up), but got %d: %v", len(frames), frames)
+	}
+	expectFrame(t, frames[0],
+		Event{Type: EvAbs, Code: AbsMtTrackingID, Value: 0},
+		Event{Type: EvAbs, Code: AbsMtPositionX, Value: 450},
+		Event{Type: EvAbs, Code: AbsMtPositionY, Value: 250},
+		Event{Type: EvAbs, Code: AbsMtSlot, Value: 1},
+		Event{Type: EvAbs, Code: AbsMtTrackingID, Value: 1},
+		Event{Type: EvAbs, Code: AbsMtPositionX, Value: 550},
+		Event{Type: EvAbs, Code: AbsMtPositionY, Value: 250},
+		Event{Type: EvKey, Code: ButtonTouch, Value: 1},
+		Event{Type: EvKey, Code: ButtonToolDoubleTap, Value: 1})
+	expectFrame(t, frames[1],
+		Event{Type: EvAbs, Code: AbsMtSlot, Value: 0},
+		Event{Type: EvAbs, Code: AbsMtPositionX, Value: 400},
+		Event{Type: EvAbs, Code: AbsMtSlot, Value: 1},
+		Event{Type: EvAbs, Code: AbsMtPositionX, Value: 600})
+	expectFrame(t, frames[2],
+		Event{Type: EvAbs, Code: AbsMtSlot, Value: 0},
+		Event{Type: EvAbs, Code: AbsMtPositionX, Value: 350},
+		Event{Type: EvAbs, Code: AbsMtSlot, Value: 1},
+		Event{Type: EvAbs, Code: AbsMtPositionX, Value: 650})
+	expectFrame(t, frames[3],
+		Event{Type: EvAbs, Code: AbsMtSlot, Value: 0},
+		Event{Type: EvAbs, Code: AbsMtTrackingID, Value: -1},
+		Event{Type: EvAbs, Code: AbsMtSlot, Value: 1},
+		Event{Type: EvAbs, Code: AbsMtTrackingID, Value: -1},
+		Event{Type: EvKey, Code: ButtonTouch, Value: 0},
+		Event{Type: EvKey, Code: ButtonToolDoubleTap, Value: 0})
+}
+
+func TestRotateKeepsFingersOpposite(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+	dev, err := CreateMultiTouch(fake.Path(), []byte("Test MultiTouch"), 0, 1000, 0, 500, 5)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual multi touch device. Last error was: %s\n", err)
+	}
+	fd := fake.Device("Test MultiTouch")
+	defer dev.Close()
+
+	err = dev.Rotate(500, 250, 100, 90, GestureOptions{Duration: 10 * time.Millisecond, StepRate: 100})
+	if err != nil {
+		t.Fatalf("Failed to rotate. Last error was: %s\n", err)
+	}
+
+	frames := splitFrames(fd.Events())
+	if len(frames) != 3 {
+		t.Fatalf("Expected 3 frames (down, 1 step, up), but got %d: %v", len(frames), frames)
+	}
+	expectFrame(t, frames[1],
+		Event{Type: EvAbs, Code: AbsMtSlot, Value: 0},
+		Event{Type: EvAbs, Code: AbsMtPositionX, Value: 500},
+		Event{Type: EvAbs, Code: AbsMtPositionY, Value: 350},
+		Event{Type: EvAbs, Code: AbsMtSlot, Value: 1},
+		Event{Type: EvAbs, Code: AbsMtPositionX, Value: 500},
+		Event{Type: EvAbs, Code: AbsMtPositionY, Value: 150})
+}
+
+func TestSwipeAndTapUseMultipleFingers(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+	dev, err := CreateMultiTouch(fake.Path(), []byte("Test MultiTouch"), 0, 1000, 0, 500, 5)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual multi touch device. Last error was: %s\n", err)
+	}
+	fd := fake.Device("Test MultiTouch")
+	defer dev.Close()
+
+	err = dev.Swipe(3, 500, 400, 500, 100, GestureOptions{Duration: 10 * time.Millisecond, StepRate: 100,
+		FingerSpacing: 50})
+	if err != nil {
+		t.Fatalf("Failed to swipe. Last error was: %s\n", err)
+	}
+	frames := splitFrames(fd.Events())
+	fd.ClearEvents()
+	if len(frames) != 3 || len(frames[0]) != 3*3+2+2 {
+		t.Fatalf("Expected three fingers to be put down in the first frame, but got %v", frames)
+	}
+	expectFrame(t, frames[2][len(frames[2])-2:],
+		Event{Type: EvKey, Code: ButtonTouch, Value: 0},
+		Event{Type: EvKey, Code: ButtonToolTripleTap, Value: 0})
+
+	err = dev.Tap(2, 10, 10, GestureOptions{Duration: time.Millisecond})
+	if err != nil {
+		t.Fatalf("Failed to tap. Last error was: %s\n", err)
+	}
+	frames = splitFrames(fd.Events())
+	if len(frames) != 2 {
+		t.Fatalf("Expected 2 frames (down, up), but got %d: %v", len(frames), frames)
+	}
+	// the fingers are clamped to the surface
+	expectFrame(t, frames[0][:4],
+		Event{Type: EvAbs, Code: AbsMtSlot, Value: 0},
+		Event{Type: EvAbs, Code: AbsMtTrackingID, Value: 3},
+		Event{Type: EvAbs, Code: AbsMtPositionX, Value: 0},
+		Event{Type: EvAbs, Code: AbsMtPositionY, Value: 10})
+}
+
+func TestLongPressHoldsFinger(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+	dev, err := CreateMultiTouch(fake.Path(), []byte("Test MultiTouch"), 0, 1000, 0, 500, 5)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual multi touch device. Last error was: %s\n", err)
+	}
+	fd := fake.Device("Test MultiTouch")
+	defer dev.Close()
+
+	start := time.Now()
+	err = dev.LongPress(100, 100, GestureOptions{Duration: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Failed to long press. Last error was: %s\n", err)
+	}
+	if time.Since(start) < 50*time.Millisecond {
+		t.Fatalf("Expected the finger to be held for at least 50ms")
+	}
+	if frames := splitFrames(fd.Events()); len(frames) != 2 {
+		t.Fatalf("Expected 2 frames (down, up), but got %d: %v", len(frames), frames)
+	}
+}
+
+func TestGestureFailsWithoutFreeContacts(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+	dev, err := CreateMultiTouch(fake.Path(), []byte("Test MultiTouch"), 0, 1000, 0, 500, 5)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual multi touch device. Last error was: %s\n", err)
+	}
+	fd := fake.Device("Test MultiTouch")
+	defer dev.Close()
+
+	for _, contact := range dev.GetContacts()[:4] {
+		err := contact.Down(1, 1)
+		if err != nil {
+			t.Fatalf("Failed to put contact down. Last error was: %s\n", err)
+		}
+	}
+	fd.ClearEvents()
+
+	err = dev.Pinch(500, 250, 100, 300, GestureOptions{})
+	if err == nil {
+		t.Fatalf("Expected pinch to fail, but no error was returned.")
+	}
+	err = dev.Tap(0, 500, 250, GestureOptions{})
+	if err == nil {
+		t.Fatalf("Expected tap without fingers to fail, but no error was returned.")
+	}
+	fd.ExpectNoEvents(t)
+}
+
+func expectFrame(t *testing.T, actual []Event, expected ...Event) {
+	t.Helper()
+	if len(actual) != len(expected) {
+		t.Fatalf("Expected frame %v, but got %v", expected, actual)
+	}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Fatalf("Expected frame %v, but got %v", expected, actual)
+		}
+	}
+}
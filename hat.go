@@ -0,0 +1,122 @@
+package uinput
+
+import "fmt"
+
+// maximum number of hats a device may have (ABS_HAT0X to ABS_HAT3Y)
+const maxHats = 4
+
+// HatPosition is one of the eight directions of a hat switch (north being up), or its center.
+type HatPosition int
+
+const (
+	HatCentered HatPosition = iota
+	HatNorth
+	HatNorthEast
+	HatEast
+	HatSouthEast
+	HatSouth
+	HatSouthWest
+	HatWest
+	HatNorthWest
+)
+
+// values of the x and y-axis of a hat for each position
+var hatPositionValues = map[HatPosition][2]int32{
+	HatCentered:  {0, 0},
+	HatNorth:     {0, -1},
+	HatNorthEast: {1, -1},
+	HatEast:      {1, 0},
+	HatSouthEast: {1, 1},
+	HatSouth:     {0, 1},
+	HatSouthWest: {-1, 1},
+	HatWest:      {-1, 0},
+	HatNorthWest: {-1, -1},
+}
+
+// hatCodes returns the codes of the x and y-axis of the hat with the given index.
+func hatCodes(hat int) (uint16, uint16) {
+	return uint16(AbsHat0X + 2*hat), uint16(AbsHat0Y + 2*hat)
+}
+
+// addHatAxes will append the axes of the given number of hats, unless they have been configured already.
+func addHatAxes(axes []AxisConfig, hats int) ([]AxisConfig, error) {
+	if hats < 0 || hats > maxHats {
+		return nil, fmt.Errorf("number of hats must be within 0 and %d, but is %d", maxHats, hats)
+	}
+	configs := axisConfigsByCode(axes)
+	for hat := 0; hat < hats; hat++ {
+		x, y := hatCodes(hat)
+		for _, code := range []uint16{x, y} {
+			if _, ok := configs[code]; !ok {
+				axes = append(axes, defaultAxisConfig(code))
+			}
+		}
+	}
+	return axes, nil
+}
+
+// sendHat will move the hat with the given index within a single frame, sending only the axes that changed. Values are
+// clamped to -1 to 1. The caller must hold the mutex.
+func (s *sentState) sendHat(deviceFile uinputFile, axes map[uint16]AxisConfig, hat int, x int32, y int32) error {
+	if hat < 0 || hat >= maxHats {
+		return fmt.Errorf("hat %d is not supported", hat)
+	}
+	codeX, codeY := hatCodes(hat)
+	_, okX := axes[codeX]
+	_, okY := axes[codeY]
+	if !okX || !okY {
+		return fmt.Errorf("hat %d is not registered", hat)
+	}
+
+	frame := newFrame(deviceFile)
+	values := map[uint16]int32{codeX: clampHat(x), codeY: clampHat(y)}
+	for _, code := range []uint16{codeX, codeY} {
+		if sent, ok := s.values[code]; !ok || sent != values[code] {
+			frame.Emit(evAbs, code, values[code])
+		}
+	}
+	err := frame.Flush()
+	if err != nil {
+		return fmt.Errorf("failed to send hat event: %v", err)
+	}
+	for code, value := range values {
+		s.values[code] = value
+	}
+	if hat == 0 {
+		s.setHatDirections(values[codeX], values[codeY])
+	}
+	return nil
+}
+
+// setHatDirections will update the directions held down using HatPress to match the given position of the first hat.
+func (s *sentState) setHatDirections(x int32, y int32) {
+	s.hatDirections = map[HatDirection]bool{
+		HatLeft:  x < 0,
+		HatRight: x > 0,
+		HatUp:    y < 0,
+		HatDown:  y > 0,
+	}
+}
+
+func clampHat(value int32) int32 {
+	if value < -1 {
+		return -1
+	} else if value > 1 {
+		return 1
+	}
+	return value
+}
+
+func (vg vGamepad) HatMove(hat int, x int32, y int32) error {
+	vg.sent.mutex.Lock()
+	defer vg.sent.mutex.Unlock()
+	return vg.sent.sendHat(vg.deviceFile, vg.axes, hat, x, y)
+}
+
+func (vg vGamepad) HatSet(hat int, position HatPosition) error {
+	values, ok := hatPositionValues[position]
+	if !ok {
+		return fmt.Errorf("hat position %d is not supported", position)
+	}
+	return vg.HatMove(hat, values[0], values[1])
+}
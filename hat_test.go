@@ -0,0 +1,128 @@
+package uinput
+
+import "testing"
+
+func TestHatDiagonalsAreSentAsOneFrame(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	vg, err := CreateGamepad(fake.Path(), []byte("Test Gamepad"), 0xDEAD, 0xBEEF)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+	defer vg.Close()
+	dev := fake.Device("Test Gamepad")
+
+	err = vg.HatSet(0, HatNorthWest)
+	if err != nil {
+		t.Fatalf("Failed to move the hat. Last error was: %s\n", err)
+	}
+	err = vg.HatSet(0, HatWest)
+	if err != nil {
+		t.Fatalf("Failed to move the hat. Last error was: %s\n", err)
+	}
+	err = vg.HatSet(0, HatWest)
+	if err != nil {
+		t.Fatalf("Failed to move the hat. Last error was: %s\n", err)
+	}
+	dev.ExpectEvents(t,
+		Event{Type: EvAbs, Code: AbsHat0X, Value: -1},
+		Event{Type: EvAbs, Code: AbsHat0Y, Value: -1},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvAbs, Code: AbsHat0Y, Value: 0},
+		Event{Type: EvSyn, Code: SynReport})
+
+	// releasing the direction set using HatSet centers the hat
+	err = vg.HatRelease(HatLeft)
+	if err != nil {
+		t.Fatalf("Failed to release the hat. Last error was: %s\n", err)
+	}
+	dev.ExpectEvents(t,
+		Event{Type: EvAbs, Code: AbsHat0X, Value: 0},
+		Event{Type: EvSyn, Code: SynReport})
+}
+
+func TestHatReleasesCompose(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	vg, err := CreateGamepad(fake.Path(), []byte("Test Gamepad"), 0xDEAD, 0xBEEF)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+	defer vg.Close()
+
+	steps := []struct {
+		press     bool
+		direction HatDirection
+	}{
+		{true, HatLeft},
+		{true, HatRight},
+		{false, HatLeft},
+		{false, HatRight},
+	}
+	for _, step := range steps {
+		if step.press {
+			err = vg.HatPress(step.direction)
+		} else {
+			err = vg.HatRelease(step.direction)
+		}
+		if err != nil {
+			t.Fatalf("Failed to send hat event. Last error was: %s\n", err)
+		}
+	}
+	fake.Device("Test Gamepad").ExpectEvents(t,
+		Event{Type: EvAbs, Code: AbsHat0X, Value: -1},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvAbs, Code: AbsHat0X, Value: 1},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvAbs, Code: AbsHat0X, Value: 1},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvAbs, Code: AbsHat0X, Value: 0},
+		Event{Type: EvSyn, Code: SynReport})
+}
+
+func TestAdditionalHatsAreRegisteredOnDemand(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	vg, err := CreateGamepad(fake.Path(), []byte("Test Gamepad"), 0xDEAD, 0xBEEF, WithHats(3))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+	defer vg.Close()
+	dev := fake.Device("Test Gamepad")
+
+	for _, code := range []uint16{AbsHat0X, AbsHat0Y, AbsHat1X, AbsHat1Y, AbsHat2X, AbsHat2Y} {
+		if !dev.HasCode(EvAbs, code) {
+			t.Fatalf("Expected hat axis %d to be registered", code)
+		}
+	}
+	if dev.HasCode(EvAbs, AbsHat3X) {
+		t.Fatalf("Expected the fourth hat not to be registered")
+	}
+
+	err = vg.HatMove(2, 1, 5)
+	if err != nil {
+		t.Fatalf("Failed to move the hat. Last error was: %s\n", err)
+	}
+	dev.ExpectEvents(t,
+		Event{Type: EvAbs, Code: AbsHat2X, Value: 1},
+		Event{Type: EvAbs, Code: AbsHat2Y, Value: 1},
+		Event{Type: EvSyn, Code: SynReport})
+
+	err = vg.HatMove(3, 1, 0)
+	if err == nil {
+		t.Fatalf("Expected moving an unregistered hat to fail")
+	}
+}
+
+func TestTooManyHatsAreRejected(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	_, err := CreateGamepad(fake.Path(), []byte("Test Gamepad"), 0xDEAD, 0xBEEF, WithHats(5))
+	if err == nil {
+		t.Fatalf("Expected gamepad creation to fail for five hats")
+	}
+}
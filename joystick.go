@@ -0,0 +1,261 @@
+package uinput
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// limits of a joystick: BTN_TRIGGER to BTN_BASE6, followed by BTN_TRIGGER_HAPPY1 to BTN_TRIGGER_HAPPY40
+const (
+	joystickBaseButtons     = 12
+	maxJoystickButtons      = joystickBaseButtons + 40
+	defaultJoystickButtons  = joystickBaseButtons
+	defaultJoystickMaxValue = MaximumAxisValue
+)
+
+// JoystickConfig describes the controls of a joystick (like a flight stick, a throttle quadrant or a set of rudder
+// pedals). The zero value selects a flight stick with twist, throttle and twelve buttons, but no hats.
+type JoystickConfig struct {
+	// Axes of the joystick along with their ranges (e.g. AbsX, AbsY, AbsRZ for the twist of the stick, AbsThrottle,
+	// AbsRudder, AbsWheel or AbsMisc and above for additional sliders). Defaults to AbsX, AbsY, AbsRZ and AbsThrottle,
+	// ranging from -MaximumAxisValue to MaximumAxisValue.
+	Axes []AxisConfig
+	// Buttons is the number of buttons (up to 52). The first twelve buttons are reported as BTN_TRIGGER to BTN_BASE6,
+	// the others as BTN_TRIGGER_HAPPY1 to BTN_TRIGGER_HAPPY40. Defaults to 12.
+	Buttons int
+	// Hats is the number of hat switches (up to 4).
+	Hats int
+}
+
+// A Joystick is a device with an arbitrary set of axes, buttons and hats, as used by flight and racing simulators.
+// Buttons are addressed by their index (starting at 0), axes by their code. Multiple physical devices (like the stick
+// and the throttle of a HOTAS setup) may be merged into a single joystick.
+type Joystick interface {
+	// ButtonPress will press and release the button with the given index.
+	ButtonPress(button int) error
+
+	// ButtonDown will press the button with the given index. The button will not be released until ButtonUp is
+	// invoked.
+	ButtonDown(button int) error
+
+	// ButtonUp will release the button with the given index.
+	ButtonUp(button int) error
+
+	// AxisMove moves the given axis. The normalized value (-1.0:1.0) is mapped onto the range the axis was configured
+	// with, so that -1 selects the minimum (e.g. the idle position of a throttle) and 1 the maximum.
+	AxisMove(axis uint16, value float32) error
+
+	// AxesMove moves all given axes within a single frame, ordered by their codes (see AxisMove).
+	AxesMove(values map[uint16]float32) error
+
+	// HatMove moves the hat with the given index to the given position along the x-axis (-1 left, 1 right) and y-axis
+	// (-1 up, 1 down) within a single frame.
+	HatMove(hat int, x int32, y int32) error
+
+	// HatSet moves the hat with the given index to one of its eight directions (or its center), see HatMove.
+	HatSet(hat int, position HatPosition) error
+
+	// FetchSyspath will return the syspath to the device file.
+	FetchSyspath() (string, error)
+
+	EventEmitter
+
+	EventReceiver
+
+	Releaser
+
+	io.Closer
+}
+
+type vJoystick struct {
+	name       []byte
+	deviceFile uinputFile
+	config     JoystickConfig
+	axes       map[uint16]AxisConfig
+	sent       *sentState
+	reader     *eventReader
+}
+
+// CreateJoystick will create a new joystick with the given controls.
+func CreateJoystick(path string, name []byte, config JoystickConfig, options ...DeviceOption) (Joystick, error) {
+	err := validateDevicePath(path)
+	if err != nil {
+		return nil, err
+	}
+	err = validateUinputName(name)
+	if err != nil {
+		return nil, err
+	}
+	config, err = config.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+	spec, err := config.spec()
+	if err != nil {
+		return nil, err
+	}
+
+	fd, err := createDevice(path, name, spec, newDeviceOptions(options))
+	if err != nil {
+		return nil, err
+	}
+
+	return vJoystick{
+		name:       name,
+		deviceFile: fd,
+		config:     config,
+		axes:       axisConfigsByCode(spec.AbsAxes),
+		sent: &sentState{
+			pressed:       make(map[uint16]bool),
+			values:        make(map[uint16]int32),
+			hatDirections: make(map[HatDirection]bool)},
+		reader: startEventReader(fd, nil)}, nil
+}
+
+func (config JoystickConfig) withDefaults() (JoystickConfig, error) {
+	if config.Buttons < 0 || config.Buttons > maxJoystickButtons {
+		return config, fmt.Errorf("number of buttons must be within 0 and %d, but is %d",
+			maxJoystickButtons, config.Buttons)
+	}
+	for _, axis := range config.Axes {
+		if axis.Code >= AbsHat0X && axis.Code <= AbsHat3Y {
+			return config, fmt.Errorf("hats must be declared using the number of hats rather than as axes")
+		}
+		if axis.Min >= axis.Max {
+			return config, fmt.Errorf("range of axis %d is empty (%d to %d)", axis.Code, axis.Min, axis.Max)
+		}
+	}
+	if config.Buttons == 0 {
+		config.Buttons = defaultJoystickButtons
+	}
+	if len(config.Axes) == 0 {
+		for _, code := range []uint16{AbsX, AbsY, AbsRZ, AbsThrottle} {
+			config.Axes = append(config.Axes, AxisConfig{Code: code, Min: -defaultJoystickMaxValue, Max: defaultJoystickMaxValue})
+		}
+	}
+	return config, nil
+}
+
+func (config JoystickConfig) spec() (DeviceSpec, error) {
+	var keys []uint16
+	for i := 0; i < config.Buttons; i++ {
+		keys = append(keys, joystickButton(i))
+	}
+	axes, err := addHatAxes(append([]AxisConfig(nil), config.Axes...), config.Hats)
+	if err != nil {
+		return DeviceSpec{}, err
+	}
+
+	return DeviceSpec{
+		ID:      InputID{Bustype: BusUsb, Vendor: 0x4711, Product: 0x081d, Version: 1},
+		Keys:    keys,
+		AbsAxes: axes,
+	}, nil
+}
+
+// joystickButton returns the code of the button with the given index.
+func joystickButton(index int) uint16 {
+	if index < joystickBaseButtons {
+		return uint16(ButtonTrigger + index)
+	}
+	return uint16(ButtonTriggerHappy + index - joystickBaseButtons)
+}
+
+func (vj vJoystick) ButtonPress(button int) error {
+	err := vj.ButtonDown(button)
+	if err != nil {
+		return fmt.Errorf("failed to issue the press event: %v", err)
+	}
+	return vj.ButtonUp(button)
+}
+
+func (vj vJoystick) ButtonDown(button int) error {
+	return vj.sendButtonEvent(button, btnStatePressed)
+}
+
+func (vj vJoystick) ButtonUp(button int) error {
+	return vj.sendButtonEvent(button, btnStateReleased)
+}
+
+func (vj vJoystick) sendButtonEvent(button int, btnState int) error {
+	if button < 0 || button >= vj.config.Buttons {
+		return fmt.Errorf("button %d is not supported", button)
+	}
+	return sendBtnEvent(vj.deviceFile, []int{int(joystickButton(button))}, btnState)
+}
+
+func (vj vJoystick) AxisMove(axis uint16, value float32) error {
+	return vj.AxesMove(map[uint16]float32{axis: value})
+}
+
+func (vj vJoystick) AxesMove(values map[uint16]float32) error {
+	codes := make([]uint16, 0, len(values))
+	for code := range values {
+		if _, ok := vj.axes[code]; !ok || (code >= AbsHat0X && code <= AbsHat3Y) {
+			return fmt.Errorf("axis %d is not supported", code)
+		}
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+
+	vj.sent.mutex.Lock()
+	defer vj.sent.mutex.Unlock()
+
+	frame := newFrame(vj.deviceFile)
+	absValues := make(map[uint16]int32, len(codes))
+	for _, code := range codes {
+		absValues[code] = vj.axes[code].denormalize(values[code])
+		frame.Emit(evAbs, code, absValues[code])
+	}
+	err := frame.Flush()
+	if err != nil {
+		return fmt.Errorf("failed to send axis event: %v", err)
+	}
+	for code, value := range absValues {
+		vj.sent.values[code] = value
+	}
+	return nil
+}
+
+func (vj vJoystick) HatMove(hat int, x int32, y int32) error {
+	vj.sent.mutex.Lock()
+	defer vj.sent.mutex.Unlock()
+	return vj.sent.sendHat(vj.deviceFile, vj.axes, hat, x, y)
+}
+
+func (vj vJoystick) HatSet(hat int, position HatPosition) error {
+	values, ok := hatPositionValues[position]
+	if !ok {
+		return fmt.Errorf("hat position %d is not supported", position)
+	}
+	return vj.HatMove(hat, values[0], values[1])
+}
+
+func (vj vJoystick) FetchSyspath() (string, error) {
+	return fetchSyspath(vj.deviceFile)
+}
+
+// Emit will send a single raw event to the device, immediately followed by a SYN_REPORT.
+func (vj vJoystick) Emit(evType uint16, code uint16, value int32) error {
+	return emitEvent(vj.deviceFile, evType, code, value)
+}
+
+// NewFrame will create an empty frame that may be used to send multiple events at once.
+func (vj vJoystick) NewFrame() *Frame {
+	return newFrame(vj.deviceFile)
+}
+
+// SetEventHandler registers a handler that is invoked for every event sent to the device.
+func (vj vJoystick) SetEventHandler(handler EventHandler) {
+	vj.reader.setHandler(handler)
+}
+
+// ReleaseAll will release all buttons that are currently held down.
+func (vj vJoystick) ReleaseAll() error {
+	return releaseAll(vj.deviceFile)
+}
+
+func (vj vJoystick) Close() error {
+	return closeDevice(vj.deviceFile)
+}
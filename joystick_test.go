@@ -0,0 +1,110 @@
+package uinput
+
+import "testing"
+
+func TestJoystickDefaults(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+	dev, err := CreateJoystick(fake.Path(), []byte("Test Joystick"), JoystickConfig{})
+	if err != nil {
+		t.Fatalf("Failed to create the virtual joystick. Last error was: %s\n", err)
+	}
+	fd := fake.Device("Test Joystick")
+	defer dev.Close()
+
+	fd.ExpectCodes(t, EvKey, ButtonTrigger, ButtonThumb, ButtonThumb2, ButtonTop, ButtonTop2, ButtonPinkie,
+		ButtonBase, ButtonBase2, ButtonBase3, ButtonBase4, ButtonBase5, ButtonBase6)
+	fd.ExpectCodes(t, EvAbs, AbsX, AbsY, AbsRZ, AbsThrottle)
+}
+
+func TestHOTASJoystick(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+	dev, err := CreateJoystick(fake.Path(), []byte("Test Joystick"), JoystickConfig{
+		Axes: []AxisConfig{
+			{Code: AbsX, Min: -32768, Max: 32767},
+			{Code: AbsY, Min: -32768, Max: 32767},
+			{Code: AbsThrottle, Min: 0, Max: 1023},
+			{Code: AbsRudder, Min: 0, Max: 255},
+			{Code: AbsMisc, Min: 0, Max: 255},
+		},
+		Buttons: 52,
+		Hats:    2,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create the virtual joystick. Last error was: %s\n", err)
+	}
+	fd := fake.Device("Test Joystick")
+	defer dev.Close()
+
+	if !fd.HasCode(EvKey, ButtonBase6) || !fd.HasCode(EvKey, ButtonTriggerHappy40) || fd.HasCode(EvKey, ButtonTriggerHappy40+1) {
+		t.Fatalf("Expected BTN_TRIGGER to BTN_BASE6 and BTN_TRIGGER_HAPPY1 to BTN_TRIGGER_HAPPY40 to be registered")
+	}
+	fd.ExpectCodes(t, EvAbs, AbsX, AbsY, AbsThrottle, AbsRudder, AbsMisc, AbsHat0X, AbsHat0Y, AbsHat1X, AbsHat1Y)
+	axis, _ := fd.Axis(AbsThrottle)
+	if axis.Min != 0 || axis.Max != 1023 {
+		t.Fatalf("Expected the throttle to range from 0 to 1023, but got %+v", axis)
+	}
+
+	err = dev.AxesMove(map[uint16]float32{AbsThrottle: 1, AbsX: -1, AbsRudder: 0})
+	if err != nil {
+		t.Fatalf("Failed to move the axes. Last error was: %s\n", err)
+	}
+	err = dev.ButtonPress(12)
+	if err != nil {
+		t.Fatalf("Failed to press the button. Last error was: %s\n", err)
+	}
+	err = dev.HatSet(1, HatSouthEast)
+	if err != nil {
+		t.Fatalf("Failed to move the hat. Last error was: %s\n", err)
+	}
+	fd.ExpectEvents(t,
+		Event{Type: EvAbs, Code: AbsX, Value: -32768},
+		Event{Type: EvAbs, Code: AbsThrottle, Value: 1023},
+		Event{Type: EvAbs, Code: AbsRudder, Value: 127},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvKey, Code: ButtonTriggerHappy1, Value: 1},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvKey, Code: ButtonTriggerHappy1, Value: 0},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvAbs, Code: AbsHat1X, Value: 1},
+		Event{Type: EvAbs, Code: AbsHat1Y, Value: 1},
+		Event{Type: EvSyn, Code: SynReport})
+}
+
+func TestJoystickRejectsUnknownControls(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+	dev, err := CreateJoystick(fake.Path(), []byte("Test Joystick"), JoystickConfig{Buttons: 4})
+	if err != nil {
+		t.Fatalf("Failed to create the virtual joystick. Last error was: %s\n", err)
+	}
+	defer dev.Close()
+
+	if dev.ButtonDown(4) == nil {
+		t.Fatalf("Expected pressing an unknown button to fail")
+	}
+	if dev.AxisMove(AbsBrake, 1) == nil {
+		t.Fatalf("Expected moving an unknown axis to fail")
+	}
+	if dev.HatMove(0, 1, 0) == nil {
+		t.Fatalf("Expected moving an unknown hat to fail")
+	}
+}
+
+func TestJoystickCreationFailsForInvalidConfig(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	for _, config := range []JoystickConfig{
+		{Buttons: 53},
+		{Hats: 5},
+		{Axes: []AxisConfig{{Code: AbsHat0X, Min: -1, Max: 1}}},
+		{Axes: []AxisConfig{{Code: AbsThrottle, Min: 0, Max: 0}}},
+	} {
+		_, err := CreateJoystick(fake.Path(), []byte("Test Joystick"), config)
+		if err == nil {
+			t.Fatalf("Expected joystick creation to fail for %+v", config)
+		}
+	}
+}
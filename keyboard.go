@@ -3,7 +3,6 @@ package uinput
 import (
 	"fmt"
 	"io"
-	"os"
 )
 
 // A Keyboard is an key event output device. It is used to
@@ -21,20 +20,54 @@ type Keyboard interface {
 	// The key can be any of the predefined keycodes from keycodes.go.
 	KeyUp(key int) error
 
+	// KeyCombo will press the given keys in order and release them in reverse order (e.g. KeyLeftctrl, KeyLeftalt,
+	// KeyDelete). All key presses are sent as a single frame, as are all key releases. Use ParseKeyCombo in order to
+	// obtain the keys from a string like "ctrl+shift+t".
+	KeyCombo(keys ...int) error
+
+	// TypeRune will type a single character using the layout of the keyboard, including any modifiers (Shift, AltGr)
+	// and dead keys that are required to produce it.
+	TypeRune(r rune) error
+
+	// TypeString will type the given text using the layout of the keyboard. Nothing is typed if the text contains a
+	// character that is not part of the layout. Note that the state of caps lock is not taken into account.
+	TypeString(text string) error
+
+	// LEDs will return the current state of the keyboard leds (num lock, caps lock, scroll lock, compose and kana),
+	// as set by the system.
+	LEDs() LEDState
+
 	// FetchSysPath will return the syspath to the device file.
 	FetchSyspath() (string, error)
 
+	EventEmitter
+
+	EventReceiver
+
+	Releaser
+
 	io.Closer
 }
 
 type vKeyboard struct {
 	name       []byte
-	deviceFile *os.File
+	deviceFile uinputFile
+	reader     *eventReader
+	layout     *Layout
 }
 
 // CreateKeyboard will create a new keyboard using the given uinput
-// device path of the uinput device.
-func CreateKeyboard(path string, name []byte) (Keyboard, error) {
+// device path of the uinput device. Text is typed using the US layout.
+func CreateKeyboard(path string, name []byte, options ...DeviceOption) (Keyboard, error) {
+	return CreateKeyboardWithLayout(path, name, LayoutUS, options...)
+}
+
+// CreateKeyboardWithLayout will create a new keyboard that types text using the given layout (e.g. LayoutDE). The
+// layout needs to match the layout the system uses for the keyboard.
+func CreateKeyboardWithLayout(path string, name []byte, layout *Layout, options ...DeviceOption) (Keyboard, error) {
+	if layout == nil {
+		return nil, fmt.Errorf("layout must not be nil")
+	}
 	err := validateDevicePath(path)
 	if err != nil {
 		return nil, err
@@ -44,12 +77,12 @@ func CreateKeyboard(path string, name []byte) (Keyboard, error) {
 		return nil, err
 	}
 
-	fd, err := createVKeyboardDevice(path, name)
+	fd, err := createVKeyboardDevice(path, name, newDeviceOptions(options))
 	if err != nil {
 		return nil, err
 	}
 
-	return vKeyboard{name: name, deviceFile: fd}, nil
+	return vKeyboard{name: name, deviceFile: fd, reader: startEventReader(fd, nil), layout: layout}, nil
 }
 
 // KeyPress will issue a single key press (push down a key and then immediately release it).
@@ -86,13 +119,106 @@ func (vk vKeyboard) KeyUp(key int) error {
 	return sendBtnEvent(vk.deviceFile, []int{key}, btnStateReleased)
 }
 
+// KeyCombo will press the given keys in order within a single frame and release them in reverse order afterwards.
+func (vk vKeyboard) KeyCombo(keys ...int) error {
+	if len(keys) == 0 {
+		return fmt.Errorf("failed to perform KeyCombo. No keys given")
+	}
+	for _, key := range keys {
+		if !keyCodeInRange(key) {
+			return fmt.Errorf("failed to perform KeyCombo. Code %d is not in range", key)
+		}
+	}
+
+	frame := newFrame(vk.deviceFile)
+	for _, key := range keys {
+		frame.Emit(evKey, uint16(key), btnStatePressed)
+	}
+	err := frame.Flush()
+	if err != nil {
+		return fmt.Errorf("failed to press key combination: %v", err)
+	}
+
+	for i := len(keys) - 1; i >= 0; i-- {
+		frame.Emit(evKey, uint16(keys[i]), btnStateReleased)
+	}
+	err = frame.Flush()
+	if err != nil {
+		return fmt.Errorf("failed to release key combination: %v", err)
+	}
+	return nil
+}
+
+// TypeRune will type a single character using the layout of the keyboard.
+func (vk vKeyboard) TypeRune(r rune) error {
+	return vk.TypeString(string(r))
+}
+
+// TypeString will type the given text using the layout of the keyboard.
+func (vk vKeyboard) TypeString(text string) error {
+	strokes, err := vk.layout.keyStrokesFor(text)
+	if err != nil {
+		return fmt.Errorf("failed to type text: %v", err)
+	}
+	for _, stroke := range strokes {
+		err = vk.typeKeyStroke(stroke)
+		if err != nil {
+			return fmt.Errorf("failed to type text: %v", err)
+		}
+	}
+	return nil
+}
+
+// typeKeyStroke will press the modifiers of the key stroke, press and release the key and release the modifiers again
+// (in reverse order).
+func (vk vKeyboard) typeKeyStroke(stroke KeyStroke) error {
+	modifiers := stroke.Modifiers.keys()
+	for _, modifier := range modifiers {
+		err := sendBtnEvent(vk.deviceFile, []int{modifier}, btnStatePressed)
+		if err != nil {
+			return err
+		}
+	}
+	err := vk.KeyPress(stroke.Key)
+	if err != nil {
+		return err
+	}
+	for i := len(modifiers) - 1; i >= 0; i-- {
+		err = sendBtnEvent(vk.deviceFile, []int{modifiers[i]}, btnStateReleased)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Emit will send a single raw event to the device, immediately followed by a SYN_REPORT.
+func (vk vKeyboard) Emit(evType uint16, code uint16, value int32) error {
+	return emitEvent(vk.deviceFile, evType, code, value)
+}
+
+// NewFrame will create an empty frame that may be used to send multiple events at once.
+func (vk vKeyboard) NewFrame() *Frame {
+	return newFrame(vk.deviceFile)
+}
+
+// SetEventHandler registers a handler that is invoked for every event sent to the device.
+func (vk vKeyboard) SetEventHandler(handler EventHandler) {
+	vk.reader.setHandler(handler)
+}
+
+// ReleaseAll will release all keys, buttons and touch contacts that are currently held down.
+func (vk vKeyboard) ReleaseAll() error {
+	return releaseAll(vk.deviceFile)
+}
+
 // Close will close the device and free resources.
 // It's usually a good idea to use defer to call this function.
 func (vk vKeyboard) Close() error {
 	return closeDevice(vk.deviceFile)
 }
 
-func createVKeyboardDevice(path string, name []byte) (fd *os.File, err error) {
+func createVKeyboardDevice(path string, name []byte, options deviceOptions) (fd uinputFile, err error) {
 	deviceFile, err := createDeviceFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create virtual keyboard device: %v", err)
@@ -113,20 +239,42 @@ func createVKeyboardDevice(path string, name []byte) (fd *os.File, err error) {
 		}
 	}
 
+	err = registerDevice(deviceFile, uintptr(evLed))
+	if err != nil {
+		deviceFile.Close()
+		return nil, fmt.Errorf("failed to register keyboard leds: %v", err)
+	}
+
+	// register leds (in order to receive lock state changes)
+	for _, led := range []int{LedNumLock, LedCapsLock, LedScrollLock, LedCompose, LedKana} {
+		err = ioctl(deviceFile, uiSetLedBit, uintptr(led))
+		if err != nil {
+			deviceFile.Close()
+			return nil, fmt.Errorf("failed to register led %d: %v", led, err)
+		}
+	}
+
 	return createUsbDevice(deviceFile,
-		uinputUserDev{
+		uinputSetup{
 			Name: toUinputName(name),
 			ID: inputID{
 				Bustype: busUsb,
 				Vendor:  0x4711,
 				Product: 0x0815,
-				Version: 1}})
+				Version: 1}},
+		nil,
+		options)
 }
 
 func keyCodeInRange(key int) bool {
 	return key >= keyReserved && key <= keyMax
 }
 
+// LEDs will return the current state of the keyboard leds, as set by the system.
+func (vk vKeyboard) LEDs() LEDState {
+	return vk.reader.ledState()
+}
+
 func (vk vKeyboard) FetchSyspath() (string, error) {
 	return fetchSyspath(vk.deviceFile)
 }
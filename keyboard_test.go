@@ -192,3 +192,117 @@ func TestKeyboardSyspath(t *testing.T) {
 	}
 	t.Logf("Syspath: %s", sysPath)
 }
+
+func TestTypeStringUsesModifiersAndDeadKeys(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	vk, err := CreateKeyboardWithLayout(fake.Path(), []byte("Test Basic Keyboard"), LayoutDE)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	defer vk.Close()
+
+	err = vk.TypeString("Zé")
+	if err != nil {
+		t.Fatalf("Failed to type text. Last error was: %s\n", err)
+	}
+
+	var expected []Event
+	for _, key := range []struct {
+		code  uint16
+		value int32
+	}{
+		{KeyLeftshift, 1}, {KeyY, 1}, {KeyY, 0}, {KeyLeftshift, 0},
+		{KeyEqual, 1}, {KeyEqual, 0}, {KeyE, 1}, {KeyE, 0},
+	} {
+		expected = append(expected, Event{Type: EvKey, Code: key.code, Value: key.value}, Event{Type: EvSyn})
+	}
+	fake.Device("Test Basic Keyboard").ExpectEvents(t, expected...)
+}
+
+func TestTypeStringFailsOnUnknownCharacter(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	vk, err := CreateKeyboard(fake.Path(), []byte("Test Basic Keyboard"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	defer vk.Close()
+
+	err = vk.TypeString("abc€")
+	if err == nil {
+		t.Fatalf("Expected TypeString to fail, but no error was returned.")
+	}
+	fake.Device("Test Basic Keyboard").ExpectNoEvents(t)
+}
+
+func TestKeyboardCreationFailsWithoutLayout(t *testing.T) {
+	expected := "layout must not be nil"
+	_, err := CreateKeyboardWithLayout("/dev/uinput", []byte("KeyboardDevice"), nil)
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected: %s\nActual: %v", expected, err)
+	}
+}
+
+func TestKeyComboIsSentAsTwoFrames(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	vk, err := CreateKeyboard(fake.Path(), []byte("Test Basic Keyboard"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	defer vk.Close()
+
+	err = vk.KeyCombo(KeyLeftctrl, KeyLeftalt, KeyDelete)
+	if err != nil {
+		t.Fatalf("Failed to send key combination. Last error was: %s\n", err)
+	}
+	fake.Device("Test Basic Keyboard").ExpectEvents(t,
+		Event{Type: EvKey, Code: KeyLeftctrl, Value: 1},
+		Event{Type: EvKey, Code: KeyLeftalt, Value: 1},
+		Event{Type: EvKey, Code: KeyDelete, Value: 1},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvKey, Code: KeyDelete, Value: 0},
+		Event{Type: EvKey, Code: KeyLeftalt, Value: 0},
+		Event{Type: EvKey, Code: KeyLeftctrl, Value: 0},
+		Event{Type: EvSyn, Code: SynReport})
+}
+
+func TestKeyComboFailsOnInvalidKeys(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	vk, err := CreateKeyboard(fake.Path(), []byte("Test Basic Keyboard"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	defer vk.Close()
+
+	err = vk.KeyCombo()
+	if err == nil {
+		t.Fatalf("Expected KeyCombo to fail without keys, but no error was returned.")
+	}
+	err = vk.KeyCombo(KeyLeftctrl, keyMax+1)
+	if err == nil {
+		t.Fatalf("Expected KeyCombo to fail on out of range key, but no error was returned.")
+	}
+	fake.Device("Test Basic Keyboard").ExpectNoEvents(t)
+}
+
+func TestKeyboardAppliesOptions(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	vk, err := CreateKeyboard(fake.Path(), []byte("Test Basic Keyboard"), WithProperties(PropPointer))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	defer vk.Close()
+
+	if !fake.Device("Test Basic Keyboard").HasProperty(PropPointer) {
+		t.Fatalf("Expected the keyboard to declare INPUT_PROP_POINTER")
+	}
+}
@@ -248,12 +248,91 @@ const (
 	KeyMicmute          = 248 /*Mute/UnmuteTheMicrophone*/
 	keyMax              = 248 // highest key currently defined in this keyboard api
 
+	Button0 = 0x100 // express keys of tablet pads (and generic buttons)
+	Button1 = 0x101
+	Button2 = 0x102
+	Button3 = 0x103
+	Button4 = 0x104
+	Button5 = 0x105
+	Button6 = 0x106
+	Button7 = 0x107
+	Button8 = 0x108
+	Button9 = 0x109
+
+	ButtonLeft    = 0x110
+	ButtonRight   = 0x111
+	ButtonMiddle  = 0x112
+	ButtonSide    = 0x113
+	ButtonExtra   = 0x114
+	ButtonForward = 0x115
+	ButtonBack    = 0x116
+	ButtonTask    = 0x117
+
+	ButtonJoystick = 0x120
+
+	ButtonTrigger = 0x120
+	ButtonThumb   = 0x121
+	ButtonThumb2  = 0x122
+	ButtonTop     = 0x123
+	ButtonTop2    = 0x124
+	ButtonPinkie  = 0x125
+	ButtonBase    = 0x126
+	ButtonBase2   = 0x127
+	ButtonBase3   = 0x128
+	ButtonBase4   = 0x129
+	ButtonBase5   = 0x12a
+	ButtonBase6   = 0x12b
+
+	ButtonTriggerHappy = 0x2c0 // additional buttons of joysticks
+
+	ButtonTriggerHappy1  = 0x2c0
+	ButtonTriggerHappy2  = 0x2c1
+	ButtonTriggerHappy3  = 0x2c2
+	ButtonTriggerHappy4  = 0x2c3
+	ButtonTriggerHappy5  = 0x2c4
+	ButtonTriggerHappy6  = 0x2c5
+	ButtonTriggerHappy7  = 0x2c6
+	ButtonTriggerHappy8  = 0x2c7
+	ButtonTriggerHappy9  = 0x2c8
+	ButtonTriggerHappy10 = 0x2c9
+	ButtonTriggerHappy11 = 0x2ca
+	ButtonTriggerHappy12 = 0x2cb
+	ButtonTriggerHappy13 = 0x2cc
+	ButtonTriggerHappy14 = 0x2cd
+	ButtonTriggerHappy15 = 0x2ce
+	ButtonTriggerHappy16 = 0x2cf
+	ButtonTriggerHappy17 = 0x2d0
+	ButtonTriggerHappy18 = 0x2d1
+	ButtonTriggerHappy19 = 0x2d2
+	ButtonTriggerHappy20 = 0x2d3
+	ButtonTriggerHappy21 = 0x2d4
+	ButtonTriggerHappy22 = 0x2d5
+	ButtonTriggerHappy23 = 0x2d6
+	ButtonTriggerHappy24 = 0x2d7
+	ButtonTriggerHappy25 = 0x2d8
+	ButtonTriggerHappy26 = 0x2d9
+	ButtonTriggerHappy27 = 0x2da
+	ButtonTriggerHappy28 = 0x2db
+	ButtonTriggerHappy29 = 0x2dc
+	ButtonTriggerHappy30 = 0x2dd
+	ButtonTriggerHappy31 = 0x2de
+	ButtonTriggerHappy32 = 0x2df
+	ButtonTriggerHappy33 = 0x2e0
+	ButtonTriggerHappy34 = 0x2e1
+	ButtonTriggerHappy35 = 0x2e2
+	ButtonTriggerHappy36 = 0x2e3
+	ButtonTriggerHappy37 = 0x2e4
+	ButtonTriggerHappy38 = 0x2e5
+	ButtonTriggerHappy39 = 0x2e6
+	ButtonTriggerHappy40 = 0x2e7
+
 	ButtonGamepad = 0x130
 
 	ButtonSouth = 0x130 // A / X
 	ButtonEast  = 0x131 // X / Square
 	ButtonNorth = 0x133 // Y / Triangle
 	ButtonWest  = 0x134 // B / Circle
+	ButtonZ     = 0x135 // capture button of Nintendo controllers
 
 	ButtonBumperLeft   = 0x136 // L1
 	ButtonBumperRight  = 0x137 // R1
@@ -271,4 +350,171 @@ const (
 	ButtonDpadRight = 0x223
 
 	ButtonMode = 0x13c // This is the special button that usually bears the Xbox or Playstation logo
+
+	ButtonToolPen       = 0x140
+	ButtonToolRubber    = 0x141 // the eraser end of a pen
+	ButtonToolFinger    = 0x145
+	ButtonToolQuintTap  = 0x148 // five fingers on a touch pad
+	ButtonTouch         = 0x14a
+	ButtonStylus        = 0x14b // lower button of a pen
+	ButtonStylus2       = 0x14c // upper button of a pen
+	ButtonToolDoubleTap = 0x14d // two fingers on a touch pad
+	ButtonToolTripleTap = 0x14e // three fingers on a touch pad
+	ButtonToolQuadTap   = 0x14f // four fingers on a touch pad
+)
+
+// absolute axis codes as defined in input-event-codes.h
+const (
+	AbsX         = 0x00
+	AbsY         = 0x01
+	AbsZ         = 0x02
+	AbsRX        = 0x03
+	AbsRY        = 0x04
+	AbsRZ        = 0x05
+	AbsThrottle  = 0x06
+	AbsRudder    = 0x07
+	AbsWheel     = 0x08
+	AbsGas       = 0x09
+	AbsBrake     = 0x0a
+	AbsHat0X     = 0x10
+	AbsHat0Y     = 0x11
+	AbsHat1X     = 0x12
+	AbsHat1Y     = 0x13
+	AbsHat2X     = 0x14
+	AbsHat2Y     = 0x15
+	AbsHat3X     = 0x16
+	AbsHat3Y     = 0x17
+	AbsPressure  = 0x18
+	AbsDistance  = 0x19
+	AbsTiltX     = 0x1a
+	AbsTiltY     = 0x1b
+	AbsToolWidth = 0x1c
+	AbsVolume    = 0x20
+	AbsMisc      = 0x28
+
+	AbsMtSlot        = 0x2f
+	AbsMtTouchMajor  = 0x30
+	AbsMtTouchMinor  = 0x31
+	AbsMtWidthMajor  = 0x32
+	AbsMtWidthMinor  = 0x33
+	AbsMtOrientation = 0x34
+	AbsMtPositionX   = 0x35
+	AbsMtPositionY   = 0x36
+	AbsMtToolType    = 0x37
+	AbsMtBlobID      = 0x38
+	AbsMtTrackingID  = 0x39
+	AbsMtPressure    = 0x3a
+	AbsMtDistance    = 0x3b
+	AbsMtToolX       = 0x3c
+	AbsMtToolY       = 0x3d
+)
+
+// event types as defined in input-event-codes.h
+const (
+	EvSyn = 0x00
+	EvKey = 0x01
+	EvRel = 0x02
+	EvAbs = 0x03
+	EvMsc = 0x04
+	EvSw  = 0x05
+	EvLed = 0x11
+	EvSnd = 0x12
+	EvRep = 0x14
+	EvFf  = 0x15
+)
+
+// synchronization event codes as defined in input-event-codes.h
+const (
+	SynReport = 0x00
+)
+
+// relative axis codes as defined in input-event-codes.h
+const (
+	RelX      = 0x00
+	RelY      = 0x01
+	RelZ      = 0x02
+	RelRX     = 0x03
+	RelRY     = 0x04
+	RelRZ     = 0x05
+	RelHWheel = 0x06
+	RelDial   = 0x07
+	RelWheel  = 0x08
+	RelMisc   = 0x09
+
+	RelWheelHiRes  = 0x0b
+	RelHWheelHiRes = 0x0c
+)
+
+// miscellaneous event codes as defined in input-event-codes.h
+const (
+	MscSerial    = 0x00
+	MscPulseLed  = 0x01
+	MscGesture   = 0x02
+	MscRaw       = 0x03
+	MscScan      = 0x04
+	MscTimestamp = 0x05
+)
+
+// led codes as defined in input-event-codes.h
+const (
+	LedNumLock    = 0x00
+	LedCapsLock   = 0x01
+	LedScrollLock = 0x02
+	LedCompose    = 0x03
+	LedKana       = 0x04
+	LedSleep      = 0x05
+	LedSuspend    = 0x06
+	LedMute       = 0x07
+	LedMisc       = 0x08
+	LedMail       = 0x09
+	LedCharging   = 0x0a
+)
+
+// sound codes as defined in input-event-codes.h
+const (
+	SndClick = 0x00
+	SndBell  = 0x01
+	SndTone  = 0x02
+)
+
+// switch codes as defined in input-event-codes.h
+const (
+	SwLid                = 0x00
+	SwTabletMode         = 0x01
+	SwHeadphoneInsert    = 0x02
+	SwRfkillAll          = 0x03
+	SwMicrophoneInsert   = 0x04
+	SwDock               = 0x05
+	SwLineoutInsert      = 0x06
+	SwJackPhysicalInsert = 0x07
+	SwVideooutInsert     = 0x08
+	SwCameraLensCover    = 0x09
+	SwKeypadSlide        = 0x0a
+	SwFrontProximity     = 0x0b
+	SwRotateLock         = 0x0c
+	SwLineinInsert       = 0x0d
+	SwMuteDevice         = 0x0e
+	SwPenInserted        = 0x0f
+	SwMachineCover       = 0x10
+)
+
+// input properties as defined in input-event-codes.h
+const (
+	PropPointer       = 0x00
+	PropDirect        = 0x01
+	PropButtonpad     = 0x02
+	PropSemiMt        = 0x03
+	PropTopButtonpad  = 0x04
+	PropPointingStick = 0x05
+	PropAccelerometer = 0x06
+)
+
+// bus types as defined in input.h
+const (
+	BusPci       = 0x01
+	BusUsb       = 0x03
+	BusBluetooth = 0x05
+	BusVirtual   = 0x06
+	BusI2c       = 0x18
+	BusHost      = 0x19
 )
@@ -0,0 +1,71 @@
+package uinput
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ParseKeyCombo will translate a key combination like "ctrl+shift+t" or "Super+Shift+S" into key codes (see
+// keycodes.go), which may be passed to KeyCombo. Keys are separated by "+" and matched case-insensitively. Modifiers
+// are "ctrl", "shift", "alt", "altgr" and "super" (as well as their left and right variants like "rctrl"), other keys
+// are given by name (e.g. "a", "5", "f4", "enter", "esc", "delete", "pageup", "left", "plus") or by the character
+// they produce on a US keyboard without shift (e.g. "-", "[" or "/").
+func ParseKeyCombo(combo string) ([]int, error) {
+	if strings.TrimSpace(combo) == "" {
+		return nil, fmt.Errorf("key combination must not be empty")
+	}
+
+	var keys []int
+	for _, name := range strings.Split(combo, "+") {
+		key, err := parseKeyName(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse key combination %q: %v", combo, err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func parseKeyName(name string) (int, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return 0, fmt.Errorf("key name must not be empty")
+	}
+	if key, ok := keyNames[name]; ok {
+		return key, nil
+	}
+	if utf8.RuneCountInString(name) == 1 {
+		r, _ := utf8.DecodeRuneInString(name)
+		strokes, ok := LayoutUS.KeyStrokes(r)
+		if ok && len(strokes) == 1 && strokes[0].Modifiers == 0 {
+			return strokes[0].Key, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown key %q", name)
+}
+
+// keyNames maps the names accepted by ParseKeyCombo to key codes. Letters, digits and other characters are resolved
+// using the US layout.
+var keyNames = map[string]int{
+	"ctrl": KeyLeftctrl, "control": KeyLeftctrl, "lctrl": KeyLeftctrl, "rctrl": KeyRightctrl,
+	"shift": KeyLeftshift, "lshift": KeyLeftshift, "rshift": KeyRightshift,
+	"alt": KeyLeftalt, "lalt": KeyLeftalt, "ralt": KeyRightalt, "altgr": KeyRightalt,
+	"super": KeyLeftmeta, "meta": KeyLeftmeta, "win": KeyLeftmeta, "cmd": KeyLeftmeta,
+	"lsuper": KeyLeftmeta, "rsuper": KeyRightmeta,
+
+	"enter": KeyEnter, "return": KeyEnter, "esc": KeyEsc, "escape": KeyEsc, "tab": KeyTab, "space": KeySpace,
+	"backspace": KeyBackspace, "delete": KeyDelete, "del": KeyDelete, "insert": KeyInsert, "ins": KeyInsert,
+	"home": KeyHome, "end": KeyEnd, "pageup": KeyPageup, "pgup": KeyPageup, "pagedown": KeyPagedown,
+	"pgdn": KeyPagedown, "up": KeyUp, "down": KeyDown, "left": KeyLeft, "right": KeyRight,
+	"print": KeySysrq, "printscreen": KeySysrq, "sysrq": KeySysrq, "pause": KeyPause, "menu": KeyCompose,
+	"capslock": KeyCapslock, "numlock": KeyNumlock, "scrolllock": KeyScrolllock,
+	"plus": KeyEqual, "minus": KeyMinus, "equal": KeyEqual, "comma": KeyComma, "period": KeyDot, "dot": KeyDot,
+	"slash": KeySlash, "backslash": KeyBackslash, "semicolon": KeySemicolon, "apostrophe": KeyApostrophe,
+	"grave": KeyGrave,
+
+	"f1": KeyF1, "f2": KeyF2, "f3": KeyF3, "f4": KeyF4, "f5": KeyF5, "f6": KeyF6,
+	"f7": KeyF7, "f8": KeyF8, "f9": KeyF9, "f10": KeyF10, "f11": KeyF11, "f12": KeyF12,
+	"f13": KeyF13, "f14": KeyF14, "f15": KeyF15, "f16": KeyF16, "f17": KeyF17, "f18": KeyF18,
+	"f19": KeyF19, "f20": KeyF20, "f21": KeyF21, "f22": KeyF22, "f23": KeyF23, "f24": KeyF24,
+}
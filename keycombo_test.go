@@ -0,0 +1,37 @@
+package uinput
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseKeyCombo(t *testing.T) {
+	tests := map[string][]int{
+		"ctrl+shift+t":        {KeyLeftctrl, KeyLeftshift, KeyT},
+		"Super+Shift+S":       {KeyLeftmeta, KeyLeftshift, KeyS},
+		"ctrl + alt + delete": {KeyLeftctrl, KeyLeftalt, KeyDelete},
+		"alt+F4":              {KeyLeftalt, KeyF4},
+		"ctrl+plus":           {KeyLeftctrl, KeyEqual},
+		"ctrl+-":              {KeyLeftctrl, KeyMinus},
+		"rctrl+1":             {KeyRightctrl, Key1},
+		"enter":               {KeyEnter},
+	}
+	for combo, expected := range tests {
+		keys, err := ParseKeyCombo(combo)
+		if err != nil {
+			t.Fatalf("Failed to parse %q. Last error was: %s\n", combo, err)
+		}
+		if !reflect.DeepEqual(keys, expected) {
+			t.Fatalf("Expected %q to be parsed as %v, but got %v", combo, expected, keys)
+		}
+	}
+}
+
+func TestParseKeyComboFailsOnInvalidInput(t *testing.T) {
+	for _, combo := range []string{"", "ctrl+", "ctrl++t", "ctrl+hyper", "shift+A+ä"} {
+		_, err := ParseKeyCombo(combo)
+		if err == nil {
+			t.Fatalf("Expected parsing %q to fail, but no error was returned.", combo)
+		}
+	}
+}
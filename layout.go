@@ -0,0 +1,276 @@
+package uinput
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Modifier is a set of modifier keys that need to be held down while a key is pressed.
+type Modifier uint8
+
+const (
+	// ModShift requires the (left) shift key to be held down.
+	ModShift Modifier = 1 << iota
+	// ModAltGr requires the AltGr (right alt) key to be held down.
+	ModAltGr
+)
+
+// keys will return the key codes of the modifiers in the order they need to be pressed.
+func (m Modifier) keys() []int {
+	var keys []int
+	if m&ModShift != 0 {
+		keys = append(keys, KeyLeftshift)
+	}
+	if m&ModAltGr != 0 {
+		keys = append(keys, KeyRightalt)
+	}
+	return keys
+}
+
+// count will return the number of modifiers in the set.
+func (m Modifier) count() int {
+	return len(m.keys())
+}
+
+// KeyStroke is a single press of a key while holding down the given modifiers.
+type KeyStroke struct {
+	Key       int
+	Modifiers Modifier
+}
+
+// A Layout maps characters to the key strokes that produce them on a keyboard using the same layout. Characters that
+// require a dead key are mapped to more than one key stroke (e.g. the dead acute followed by "e" in order to type "é").
+type Layout struct {
+	name    string
+	strokes map[rune][]KeyStroke
+}
+
+// NewLayout will create a new layout that only maps newline and tab characters. Use Map to add further characters.
+func NewLayout(name string) *Layout {
+	l := &Layout{name: name, strokes: make(map[rune][]KeyStroke)}
+	l.Map('\n', KeyStroke{Key: KeyEnter})
+	l.Map('\t', KeyStroke{Key: KeyTab})
+	return l
+}
+
+// Name will return the name of the layout.
+func (l *Layout) Name() string {
+	return l.name
+}
+
+// Map will assign the given key strokes to a character, replacing any previous mapping.
+func (l *Layout) Map(r rune, strokes ...KeyStroke) {
+	l.strokes[r] = append([]KeyStroke(nil), strokes...)
+}
+
+// KeyStrokes will return the key strokes that produce the given character and whether the character is part of the
+// layout at all.
+func (l *Layout) KeyStrokes(r rune) ([]KeyStroke, bool) {
+	strokes, ok := l.strokes[r]
+	return strokes, ok
+}
+
+// keyStrokesFor will return the key strokes for the whole text, failing if any character is not part of the layout.
+func (l *Layout) keyStrokesFor(text string) ([]KeyStroke, error) {
+	var strokes []KeyStroke
+	for _, r := range text {
+		s, ok := l.strokes[r]
+		if !ok {
+			return nil, fmt.Errorf("character %q is not part of layout %s", r, l.name)
+		}
+		strokes = append(strokes, s...)
+	}
+	return strokes, nil
+}
+
+// mapIfSimpler will assign the key strokes to a character unless it is already mapped to strokes that are easier to
+// type (fewer strokes or fewer modifiers).
+func (l *Layout) mapIfSimpler(r rune, strokes ...KeyStroke) {
+	existing, ok := l.strokes[r]
+	if ok && (len(existing) < len(strokes) ||
+		len(existing) == len(strokes) && existing[0].Modifiers.count() <= strokes[0].Modifiers.count()) {
+		return
+	}
+	l.Map(r, strokes...)
+}
+
+var (
+	xkbComment = regexp.MustCompile(`//[^\n]*`)
+	xkbKey     = regexp.MustCompile(`key\s*<(\w+)>\s*\{([^}]*)\}`)
+	xkbLevels  = regexp.MustCompile(`(?:symbols\[\w+\]\s*=\s*)?\[([^\]]*)\]`)
+)
+
+// LoadXKBLayout will read a layout from an XKB symbols description, e.g.
+//
+//	key <AE01> { [ 1, exclam, onesuperior, exclamdown ] };
+//	key <AE12> { [ dead_acute, dead_grave ] };
+//
+// Only the first group of each key is used. The four levels are mapped to no modifier, Shift, AltGr and Shift+AltGr.
+// Characters that can be composed using one of the dead keys of the layout are mapped as well. Include statements are
+// not resolved, so the description needs to list all keys. Keys and keysyms that are unknown to this package are
+// skipped.
+func LoadXKBLayout(name string, description io.Reader) (*Layout, error) {
+	raw, err := ioutil.ReadAll(bufio.NewReader(description))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read layout description: %v", err)
+	}
+	text := xkbComment.ReplaceAllString(string(raw), "")
+
+	l := NewLayout(name)
+	deadKeys := make(map[string]KeyStroke)
+	found := false
+	for _, match := range xkbKey.FindAllStringSubmatch(text, -1) {
+		key, ok := xkbKeyCodes[match[1]]
+		if !ok {
+			continue
+		}
+		levels := xkbLevels.FindStringSubmatch(match[2])
+		if levels == nil {
+			return nil, fmt.Errorf("key <%s> of layout %s does not declare any symbols", match[1], name)
+		}
+		found = true
+
+		for level, keysym := range strings.Split(levels[1], ",") {
+			if level >= len(xkbLevelModifiers) {
+				break
+			}
+			stroke := KeyStroke{Key: key, Modifiers: xkbLevelModifiers[level]}
+			keysym = strings.TrimSpace(keysym)
+			if _, ok := deadKeyCompositions[keysym]; ok {
+				if existing, ok := deadKeys[keysym]; !ok || existing.Modifiers.count() > stroke.Modifiers.count() {
+					deadKeys[keysym] = stroke
+				}
+				continue
+			}
+			if r, ok := keysymToRune(keysym); ok {
+				l.mapIfSimpler(r, stroke)
+			}
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("layout %s does not declare any known keys", name)
+	}
+
+	// resolve dead keys in a fixed order, so that the resulting layout does not depend on the map iteration order
+	var deadKeyNames []string
+	for deadKey := range deadKeys {
+		deadKeyNames = append(deadKeyNames, deadKey)
+	}
+	sort.Strings(deadKeyNames)
+	for _, deadKey := range deadKeyNames {
+		dead := deadKeys[deadKey]
+		for base, composed := range deadKeyCompositions[deadKey] {
+			if strokes, ok := l.KeyStrokes(base); ok && len(strokes) == 1 {
+				l.mapIfSimpler(composed, dead, strokes[0])
+			}
+		}
+	}
+	return l, nil
+}
+
+func mustLoadXKBLayout(name string, description string) *Layout {
+	l, err := LoadXKBLayout(name, strings.NewReader(description))
+	if err != nil {
+		panic(err)
+	}
+	return l
+}
+
+// keysymToRune will resolve a keysym name (like "exclam", "Eacute" or "U20AC") to the character it produces.
+func keysymToRune(keysym string) (rune, bool) {
+	if r, ok := keysymRunes[keysym]; ok {
+		return r, true
+	}
+	if len(keysym) > 1 && keysym[0] == 'U' {
+		code, err := strconv.ParseUint(keysym[1:], 16, 32)
+		if err == nil {
+			return rune(code), true
+		}
+	}
+	runes := []rune(keysym)
+	if len(runes) == 1 {
+		return runes[0], true
+	}
+	return 0, false
+}
+
+var xkbLevelModifiers = []Modifier{0, ModShift, ModAltGr, ModShift | ModAltGr}
+
+// xkbKeyCodes maps the XKB names of the alphanumeric section of a keyboard to key codes.
+var xkbKeyCodes = map[string]int{
+	"TLDE": KeyGrave,
+	"AE01": Key1, "AE02": Key2, "AE03": Key3, "AE04": Key4, "AE05": Key5, "AE06": Key6,
+	"AE07": Key7, "AE08": Key8, "AE09": Key9, "AE10": Key0, "AE11": KeyMinus, "AE12": KeyEqual,
+	"AD01": KeyQ, "AD02": KeyW, "AD03": KeyE, "AD04": KeyR, "AD05": KeyT, "AD06": KeyY,
+	"AD07": KeyU, "AD08": KeyI, "AD09": KeyO, "AD10": KeyP, "AD11": KeyLeftbrace, "AD12": KeyRightbrace,
+	"AC01": KeyA, "AC02": KeyS, "AC03": KeyD, "AC04": KeyF, "AC05": KeyG, "AC06": KeyH,
+	"AC07": KeyJ, "AC08": KeyK, "AC09": KeyL, "AC10": KeySemicolon, "AC11": KeyApostrophe, "BKSL": KeyBackslash,
+	"AB01": KeyZ, "AB02": KeyX, "AB03": KeyC, "AB04": KeyV, "AB05": KeyB, "AB06": KeyN,
+	"AB07": KeyM, "AB08": KeyComma, "AB09": KeyDot, "AB10": KeySlash, "LSGT": Key102Nd, "SPCE": KeySpace,
+}
+
+// keysymRunes maps the keysym names of the printable ASCII and Latin-1 characters (as well as a few others) to the
+// characters they produce.
+var keysymRunes = func() map[string]rune {
+	keysyms := map[string]rune{
+		"EuroSign":       '€',
+		"OE":             'Œ',
+		"oe":             'œ',
+		"Ydiaeresis":     'Ÿ',
+		"oneeighth":      '⅛',
+		"guillemetleft":  '«',
+		"guillemetright": '»',
+		"ordmasculine":   'º',
+		"Ooblique":       'Ø',
+		"ooblique":       'ø',
+	}
+	for _, block := range []struct {
+		first rune
+		names string
+	}{
+		{0x20, "space exclam quotedbl numbersign dollar percent ampersand apostrophe parenleft parenright asterisk " +
+			"plus comma minus period slash"},
+		{0x3a, "colon semicolon less equal greater question at"},
+		{0x5b, "bracketleft backslash bracketright asciicircum underscore grave"},
+		{0x7b, "braceleft bar braceright asciitilde"},
+		{0xa0, "nobreakspace exclamdown cent sterling currency yen brokenbar section diaeresis copyright " +
+			"ordfeminine guillemotleft notsign hyphen registered macron degree plusminus twosuperior " +
+			"threesuperior acute mu paragraph periodcentered cedilla onesuperior masculine guillemotright " +
+			"onequarter onehalf threequarters questiondown Agrave Aacute Acircumflex Atilde Adiaeresis Aring AE " +
+			"Ccedilla Egrave Eacute Ecircumflex Ediaeresis Igrave Iacute Icircumflex Idiaeresis ETH Ntilde " +
+			"Ograve Oacute Ocircumflex Otilde Odiaeresis multiply Oslash Ugrave Uacute Ucircumflex Udiaeresis " +
+			"Yacute THORN ssharp agrave aacute acircumflex atilde adiaeresis aring ae ccedilla egrave eacute " +
+			"ecircumflex ediaeresis igrave iacute icircumflex idiaeresis eth ntilde ograve oacute ocircumflex " +
+			"otilde odiaeresis division oslash ugrave uacute ucircumflex udiaeresis yacute thorn ydiaeresis"},
+	} {
+		for i, name := range strings.Fields(block.names) {
+			keysyms[name] = block.first + rune(i)
+		}
+	}
+	return keysyms
+}()
+
+// deadKeyCompositions maps the supported dead keys to the characters they produce in combination with a base
+// character. A dead key followed by space produces the spacing variant of the accent.
+var deadKeyCompositions = map[string]map[rune]rune{
+	"dead_grave":      compositions(" aeiouAEIOU", "`àèìòùÀÈÌÒÙ"),
+	"dead_acute":      compositions(" aeiouyAEIOUY", "´áéíóúýÁÉÍÓÚÝ"),
+	"dead_circumflex": compositions(" aeiouAEIOU", "^âêîôûÂÊÎÔÛ"),
+	"dead_tilde":      compositions(" anoANO", "~ãñõÃÑÕ"),
+	"dead_diaeresis":  compositions(" aeiouyAEIOU", "¨äëïöüÿÄËÏÖÜ"),
+}
+
+func compositions(base string, composed string) map[rune]rune {
+	c := make(map[rune]rune)
+	composedRunes := []rune(composed)
+	for i, r := range []rune(base) {
+		c[r] = composedRunes[i]
+	}
+	return c
+}
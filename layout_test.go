@@ -0,0 +1,92 @@
+package uinput
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBuiltInLayoutsMapCharacters(t *testing.T) {
+	tests := []struct {
+		layout   *Layout
+		char     rune
+		expected []KeyStroke
+	}{
+		{LayoutUS, 'a', []KeyStroke{{Key: KeyA}}},
+		{LayoutUS, 'A', []KeyStroke{{Key: KeyA, Modifiers: ModShift}}},
+		{LayoutUS, '@', []KeyStroke{{Key: Key2, Modifiers: ModShift}}},
+		{LayoutUS, '\n', []KeyStroke{{Key: KeyEnter}}},
+		{LayoutUK, '"', []KeyStroke{{Key: Key2, Modifiers: ModShift}}},
+		{LayoutUK, '£', []KeyStroke{{Key: Key3, Modifiers: ModShift}}},
+		{LayoutUK, '€', []KeyStroke{{Key: Key4, Modifiers: ModAltGr}}},
+		{LayoutDE, 'z', []KeyStroke{{Key: KeyY}}},
+		{LayoutDE, 'ß', []KeyStroke{{Key: KeyMinus}}},
+		{LayoutDE, '@', []KeyStroke{{Key: KeyQ, Modifiers: ModAltGr}}},
+		{LayoutDE, 'é', []KeyStroke{{Key: KeyEqual}, {Key: KeyE}}},
+		{LayoutDE, 'È', []KeyStroke{{Key: KeyEqual, Modifiers: ModShift}, {Key: KeyE, Modifiers: ModShift}}},
+		{LayoutDE, '^', []KeyStroke{{Key: KeyGrave}, {Key: KeySpace}}},
+		{LayoutFR, 'a', []KeyStroke{{Key: KeyQ}}},
+		{LayoutFR, '1', []KeyStroke{{Key: Key1, Modifiers: ModShift}}},
+		{LayoutFR, 'ë', []KeyStroke{{Key: KeyLeftbrace, Modifiers: ModShift}, {Key: KeyE}}},
+	}
+
+	for _, test := range tests {
+		strokes, ok := test.layout.KeyStrokes(test.char)
+		if !ok {
+			t.Fatalf("Expected %q to be part of layout %s", test.char, test.layout.Name())
+		}
+		if !reflect.DeepEqual(strokes, test.expected) {
+			t.Fatalf("Expected %q of layout %s to be typed as %+v, but got %+v",
+				test.char, test.layout.Name(), test.expected, strokes)
+		}
+	}
+}
+
+func TestLoadXKBLayout(t *testing.T) {
+	description := `
+		xkb_symbols "basic" {
+			include "us(basic)" // includes are ignored
+			key <AE01> { [ 1, exclam, U20AC ] };
+			key <AC01> { symbols[Group1] = [ a, A ] };
+			key <AE12> { [ dead_acute ] };
+			key <RTRN> { [ Return ] };
+			key <AB01> { [ z, Z, VoidSymbol ] };
+		};`
+	layout, err := LoadXKBLayout("custom", strings.NewReader(description))
+	if err != nil {
+		t.Fatalf("Failed to load layout. Last error was: %s\n", err)
+	}
+
+	expected := map[rune][]KeyStroke{
+		'1': {{Key: Key1}},
+		'!': {{Key: Key1, Modifiers: ModShift}},
+		'€': {{Key: Key1, Modifiers: ModAltGr}},
+		'A': {{Key: KeyA, Modifiers: ModShift}},
+		'á': {{Key: KeyEqual}, {Key: KeyA}},
+		'Z': {{Key: KeyZ, Modifiers: ModShift}},
+	}
+	for char, strokes := range expected {
+		actual, ok := layout.KeyStrokes(char)
+		if !ok || !reflect.DeepEqual(actual, strokes) {
+			t.Fatalf("Expected %q to be typed as %+v, but got %+v", char, strokes, actual)
+		}
+	}
+	if _, ok := layout.KeyStrokes('y'); ok {
+		t.Fatalf("Expected 'y' not to be part of the layout")
+	}
+}
+
+func TestLoadXKBLayoutFailsWithoutKeys(t *testing.T) {
+	_, err := LoadXKBLayout("empty", strings.NewReader(`xkb_symbols "basic" { };`))
+	if err == nil {
+		t.Fatalf("Expected loading an empty layout to fail")
+	}
+}
+
+func TestKeyStrokesForUnknownCharacterFail(t *testing.T) {
+	expected := "character 'ä' is not part of layout us"
+	_, err := LayoutUS.keyStrokesFor("bär")
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected: %s\nActual: %v", expected, err)
+	}
+}
@@ -0,0 +1,220 @@
+package uinput
+
+// built-in keyboard layouts (see LoadXKBLayout for loading further layouts)
+var (
+	// LayoutUS is the US English (QWERTY) layout.
+	LayoutUS = mustLoadXKBLayout("us", xkbUS)
+	// LayoutUK is the British English (QWERTY) layout.
+	LayoutUK = mustLoadXKBLayout("gb", xkbUK)
+	// LayoutDE is the German (QWERTZ) layout, including its dead keys.
+	LayoutDE = mustLoadXKBLayout("de", xkbDE)
+	// LayoutFR is the French (AZERTY) layout, including its dead keys.
+	LayoutFR = mustLoadXKBLayout("fr", xkbFR)
+)
+
+const xkbUS = `
+key <TLDE> { [ grave, asciitilde ] };
+key <AE01> { [ 1, exclam ] };
+key <AE02> { [ 2, at ] };
+key <AE03> { [ 3, numbersign ] };
+key <AE04> { [ 4, dollar ] };
+key <AE05> { [ 5, percent ] };
+key <AE06> { [ 6, asciicircum ] };
+key <AE07> { [ 7, ampersand ] };
+key <AE08> { [ 8, asterisk ] };
+key <AE09> { [ 9, parenleft ] };
+key <AE10> { [ 0, parenright ] };
+key <AE11> { [ minus, underscore ] };
+key <AE12> { [ equal, plus ] };
+key <AD01> { [ q, Q ] };
+key <AD02> { [ w, W ] };
+key <AD03> { [ e, E ] };
+key <AD04> { [ r, R ] };
+key <AD05> { [ t, T ] };
+key <AD06> { [ y, Y ] };
+key <AD07> { [ u, U ] };
+key <AD08> { [ i, I ] };
+key <AD09> { [ o, O ] };
+key <AD10> { [ p, P ] };
+key <AD11> { [ bracketleft, braceleft ] };
+key <AD12> { [ bracketright, braceright ] };
+key <AC01> { [ a, A ] };
+key <AC02> { [ s, S ] };
+key <AC03> { [ d, D ] };
+key <AC04> { [ f, F ] };
+key <AC05> { [ g, G ] };
+key <AC06> { [ h, H ] };
+key <AC07> { [ j, J ] };
+key <AC08> { [ k, K ] };
+key <AC09> { [ l, L ] };
+key <AC10> { [ semicolon, colon ] };
+key <AC11> { [ apostrophe, quotedbl ] };
+key <BKSL> { [ backslash, bar ] };
+key <AB01> { [ z, Z ] };
+key <AB02> { [ x, X ] };
+key <AB03> { [ c, C ] };
+key <AB04> { [ v, V ] };
+key <AB05> { [ b, B ] };
+key <AB06> { [ n, N ] };
+key <AB07> { [ m, M ] };
+key <AB08> { [ comma, less ] };
+key <AB09> { [ period, greater ] };
+key <AB10> { [ slash, question ] };
+key <SPCE> { [ space ] };
+`
+
+const xkbUK = `
+key <TLDE> { [ grave, notsign, bar, bar ] };
+key <AE01> { [ 1, exclam ] };
+key <AE02> { [ 2, quotedbl ] };
+key <AE03> { [ 3, sterling ] };
+key <AE04> { [ 4, dollar, EuroSign ] };
+key <AE05> { [ 5, percent ] };
+key <AE06> { [ 6, asciicircum ] };
+key <AE07> { [ 7, ampersand ] };
+key <AE08> { [ 8, asterisk ] };
+key <AE09> { [ 9, parenleft ] };
+key <AE10> { [ 0, parenright ] };
+key <AE11> { [ minus, underscore ] };
+key <AE12> { [ equal, plus ] };
+key <AD01> { [ q, Q ] };
+key <AD02> { [ w, W ] };
+key <AD03> { [ e, E ] };
+key <AD04> { [ r, R ] };
+key <AD05> { [ t, T ] };
+key <AD06> { [ y, Y ] };
+key <AD07> { [ u, U ] };
+key <AD08> { [ i, I ] };
+key <AD09> { [ o, O ] };
+key <AD10> { [ p, P ] };
+key <AD11> { [ bracketleft, braceleft ] };
+key <AD12> { [ bracketright, braceright ] };
+key <AC01> { [ a, A ] };
+key <AC02> { [ s, S ] };
+key <AC03> { [ d, D ] };
+key <AC04> { [ f, F ] };
+key <AC05> { [ g, G ] };
+key <AC06> { [ h, H ] };
+key <AC07> { [ j, J ] };
+key <AC08> { [ k, K ] };
+key <AC09> { [ l, L ] };
+key <AC10> { [ semicolon, colon ] };
+key <AC11> { [ apostrophe, at ] };
+key <BKSL> { [ numbersign, asciitilde ] };
+key <LSGT> { [ backslash, bar ] };
+key <AB01> { [ z, Z ] };
+key <AB02> { [ x, X ] };
+key <AB03> { [ c, C ] };
+key <AB04> { [ v, V ] };
+key <AB05> { [ b, B ] };
+key <AB06> { [ n, N ] };
+key <AB07> { [ m, M ] };
+key <AB08> { [ comma, less ] };
+key <AB09> { [ period, greater ] };
+key <AB10> { [ slash, question ] };
+key <SPCE> { [ space ] };
+`
+
+const xkbDE = `
+key <TLDE> { [ dead_circumflex, degree ] };
+key <AE01> { [ 1, exclam, onesuperior, exclamdown ] };
+key <AE02> { [ 2, quotedbl, twosuperior, oneeighth ] };
+key <AE03> { [ 3, section, threesuperior, sterling ] };
+key <AE04> { [ 4, dollar, onequarter, currency ] };
+key <AE05> { [ 5, percent, onehalf ] };
+key <AE06> { [ 6, ampersand, notsign ] };
+key <AE07> { [ 7, slash, braceleft ] };
+key <AE08> { [ 8, parenleft, bracketleft ] };
+key <AE09> { [ 9, parenright, bracketright, plusminus ] };
+key <AE10> { [ 0, equal, braceright, degree ] };
+key <AE11> { [ ssharp, question, backslash, questiondown ] };
+key <AE12> { [ dead_acute, dead_grave ] };
+key <AD01> { [ q, Q, at ] };
+key <AD02> { [ w, W ] };
+key <AD03> { [ e, E, EuroSign ] };
+key <AD04> { [ r, R, paragraph, registered ] };
+key <AD05> { [ t, T ] };
+key <AD06> { [ z, Z ] };
+key <AD07> { [ u, U ] };
+key <AD08> { [ i, I ] };
+key <AD09> { [ o, O, oslash, Oslash ] };
+key <AD10> { [ p, P, thorn, THORN ] };
+key <AD11> { [ udiaeresis, Udiaeresis, dead_diaeresis ] };
+key <AD12> { [ plus, asterisk, asciitilde, macron ] };
+key <AC01> { [ a, A, ae, AE ] };
+key <AC02> { [ s, S ] };
+key <AC03> { [ d, D, eth, ETH ] };
+key <AC04> { [ f, F ] };
+key <AC05> { [ g, G ] };
+key <AC06> { [ h, H ] };
+key <AC07> { [ j, J ] };
+key <AC08> { [ k, K ] };
+key <AC09> { [ l, L ] };
+key <AC10> { [ odiaeresis, Odiaeresis, dead_acute ] };
+key <AC11> { [ adiaeresis, Adiaeresis, dead_circumflex ] };
+key <BKSL> { [ numbersign, apostrophe ] };
+key <LSGT> { [ less, greater, bar, brokenbar ] };
+key <AB01> { [ y, Y, guillemotright ] };
+key <AB02> { [ x, X, guillemotleft ] };
+key <AB03> { [ c, C, cent, copyright ] };
+key <AB04> { [ v, V ] };
+key <AB05> { [ b, B ] };
+key <AB06> { [ n, N ] };
+key <AB07> { [ m, M, mu, masculine ] };
+key <AB08> { [ comma, semicolon ] };
+key <AB09> { [ period, colon, periodcentered, division ] };
+key <AB10> { [ minus, underscore ] };
+key <SPCE> { [ space, space, nobreakspace ] };
+`
+
+const xkbFR = `
+key <TLDE> { [ twosuperior ] };
+key <AE01> { [ ampersand, 1 ] };
+key <AE02> { [ eacute, 2, asciitilde, Eacute ] };
+key <AE03> { [ quotedbl, 3, numbersign ] };
+key <AE04> { [ apostrophe, 4, braceleft ] };
+key <AE05> { [ parenleft, 5, bracketleft ] };
+key <AE06> { [ minus, 6, bar ] };
+key <AE07> { [ egrave, 7, grave, Egrave ] };
+key <AE08> { [ underscore, 8, backslash ] };
+key <AE09> { [ ccedilla, 9, asciicircum, Ccedilla ] };
+key <AE10> { [ agrave, 0, at, Agrave ] };
+key <AE11> { [ parenright, degree, bracketright ] };
+key <AE12> { [ equal, plus, braceright ] };
+key <AD01> { [ a, A, ae, AE ] };
+key <AD02> { [ z, Z, acircumflex, Acircumflex ] };
+key <AD03> { [ e, E, EuroSign ] };
+key <AD04> { [ r, R, ecircumflex, Ecircumflex ] };
+key <AD05> { [ t, T ] };
+key <AD06> { [ y, Y ] };
+key <AD07> { [ u, U, ucircumflex, Ucircumflex ] };
+key <AD08> { [ i, I, icircumflex, Icircumflex ] };
+key <AD09> { [ o, O, oe, OE ] };
+key <AD10> { [ p, P, ocircumflex, Ocircumflex ] };
+key <AD11> { [ dead_circumflex, dead_diaeresis ] };
+key <AD12> { [ dollar, sterling, currency ] };
+key <AC01> { [ q, Q ] };
+key <AC02> { [ s, S ] };
+key <AC03> { [ d, D ] };
+key <AC04> { [ f, F ] };
+key <AC05> { [ g, G ] };
+key <AC06> { [ h, H ] };
+key <AC07> { [ j, J ] };
+key <AC08> { [ k, K ] };
+key <AC09> { [ l, L ] };
+key <AC10> { [ m, M, mu ] };
+key <AC11> { [ ugrave, percent, Ugrave ] };
+key <BKSL> { [ asterisk, mu ] };
+key <LSGT> { [ less, greater ] };
+key <AB01> { [ w, W ] };
+key <AB02> { [ x, X ] };
+key <AB03> { [ c, C, copyright ] };
+key <AB04> { [ v, V ] };
+key <AB05> { [ b, B ] };
+key <AB06> { [ n, N ] };
+key <AB07> { [ comma, question ] };
+key <AB08> { [ semicolon, period ] };
+key <AB09> { [ colon, slash, periodcentered ] };
+key <AB10> { [ exclam, section ] };
+key <SPCE> { [ space, space, nobreakspace ] };
+`
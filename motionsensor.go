@@ -0,0 +1,236 @@
+package uinput
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// standard gravity in m/s², since the resolution of accelerometers is given in units per g
+const standardGravity = 9.80665
+
+// defaults of a motion sensor, following the sensors of the DualShock 4 and DualSense (see hid-playstation)
+const (
+	defaultAccelRange      = 4
+	defaultAccelResolution = 8192
+	defaultGyroRange       = 2048
+	defaultGyroResolution  = 1024
+	motionSensorFuzz       = 16
+)
+
+// suffix the kernel drivers append to the name of a controller to name its motion sensors
+const motionSensorNameSuffix = " Motion Sensors"
+
+// MotionSensorConfig describes the ranges of a motion sensor. The zero value of each field selects the values used by
+// the DualShock 4 and DualSense.
+type MotionSensorConfig struct {
+	// AccelRange is the maximum acceleration in g. Defaults to 4.
+	AccelRange int32
+	// AccelResolution of the accelerometer in units per g. Defaults to 8192.
+	AccelResolution int32
+	// GyroRange is the maximum angular velocity in degrees per second. Defaults to 2048.
+	GyroRange int32
+	// GyroResolution of the gyroscope in units per degree per second. Defaults to 1024.
+	GyroResolution int32
+}
+
+// A MotionSample holds a single reading of a motion sensor, as passed to Update.
+type MotionSample struct {
+	// acceleration along the x, y and z-axis in m/s² (including gravity)
+	AccelX float64
+	AccelY float64
+	AccelZ float64
+	// angular velocity around the x, y and z-axis in degrees per second
+	GyroX float64
+	GyroY float64
+	GyroZ float64
+	// Timestamp of the reading, which is reported in microseconds (wrapping around like MSC_TIMESTAMP does). Zero
+	// selects the time elapsed since the device was created.
+	Timestamp time.Duration
+}
+
+// A MotionSensor is the accelerometer and gyroscope of a controller. Like the sensors of real controllers, it is a
+// device of its own (declaring INPUT_PROP_ACCELEROMETER), which applications like SDL and Steam pair with the
+// controller for gyro aiming (see CreateMotionSensorForGamepad).
+type MotionSensor interface {
+	// Update will report the given reading within a single frame, followed by its timestamp (MSC_TIMESTAMP).
+	// Values beyond the range of the sensor are clamped.
+	Update(sample MotionSample) error
+
+	// FetchSyspath will return the syspath to the device file.
+	FetchSyspath() (string, error)
+
+	EventEmitter
+
+	EventReceiver
+
+	Releaser
+
+	io.Closer
+}
+
+type vMotionSensor struct {
+	name       []byte
+	deviceFile uinputFile
+	config     MotionSensorConfig
+	created    time.Time
+	reader     *eventReader
+}
+
+// CreateMotionSensor will create a new motion sensor with the given ranges.
+func CreateMotionSensor(path string, name []byte, config MotionSensorConfig, options ...DeviceOption) (MotionSensor, error) {
+	err := validateDevicePath(path)
+	if err != nil {
+		return nil, err
+	}
+	err = validateUinputName(name)
+	if err != nil {
+		return nil, err
+	}
+	config, err = config.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+
+	fd, err := createDevice(path, name, config.spec(), newDeviceOptions(options))
+	if err != nil {
+		return nil, err
+	}
+
+	return vMotionSensor{
+		name:       name,
+		deviceFile: fd,
+		config:     config,
+		created:    time.Now(),
+		reader:     startEventReader(fd, nil)}, nil
+}
+
+// CreateMotionSensorForGamepad will create the motion sensor of the given gamepad, which must have been created by
+// this package. The sensor is named after the gamepad (followed by " Motion Sensors", like the kernel drivers do) and
+// shares its identity and physical path, which allows applications to pair both devices. Options passed take
+// precedence over those of the gamepad.
+//
+// Note that uinput provides no way to set the unique identifier (uniq) of a device, so both devices report an empty
+// uniq rather than the serial number of a real controller.
+func CreateMotionSensorForGamepad(path string, gamepad Gamepad, config MotionSensorConfig, options ...DeviceOption) (MotionSensor, error) {
+	vg, ok := gamepad.(vGamepad)
+	if !ok {
+		return nil, errors.New("motion sensors may only be created for gamepads created by this package")
+	}
+
+	name := append(append([]byte(nil), vg.name...), motionSensorNameSuffix...)
+	options = append([]DeviceOption{WithID(vg.id), WithPhys(vg.phys)}, options...)
+	return CreateMotionSensor(path, name, config, options...)
+}
+
+func (config MotionSensorConfig) withDefaults() (MotionSensorConfig, error) {
+	if config.AccelRange < 0 || config.AccelResolution < 0 || config.GyroRange < 0 || config.GyroResolution < 0 {
+		return config, fmt.Errorf("ranges and resolutions of the motion sensor must not be negative")
+	}
+	if config.AccelRange == 0 {
+		config.AccelRange = defaultAccelRange
+	}
+	if config.AccelResolution == 0 {
+		config.AccelResolution = defaultAccelResolution
+	}
+	if config.GyroRange == 0 {
+		config.GyroRange = defaultGyroRange
+	}
+	if config.GyroResolution == 0 {
+		config.GyroResolution = defaultGyroResolution
+	}
+	if int64(config.AccelRange)*int64(config.AccelResolution) > math.MaxInt32 ||
+		int64(config.GyroRange)*int64(config.GyroResolution) > math.MaxInt32 {
+		return config, fmt.Errorf("ranges of the motion sensor exceed the range of an axis")
+	}
+	return config, nil
+}
+
+func (config MotionSensorConfig) spec() DeviceSpec {
+	var axes []AxisConfig
+	accelMax := config.AccelRange * config.AccelResolution
+	for _, code := range []uint16{AbsX, AbsY, AbsZ} {
+		axes = append(axes, AxisConfig{Code: code, Min: -accelMax, Max: accelMax, Fuzz: motionSensorFuzz,
+			Resolution: config.AccelResolution})
+	}
+	gyroMax := config.GyroRange * config.GyroResolution
+	for _, code := range []uint16{AbsRX, AbsRY, AbsRZ} {
+		axes = append(axes, AxisConfig{Code: code, Min: -gyroMax, Max: gyroMax, Fuzz: motionSensorFuzz,
+			Resolution: config.GyroResolution})
+	}
+
+	return DeviceSpec{
+		ID:         InputID{Bustype: BusUsb, Vendor: 0x4711, Product: 0x081c, Version: 1},
+		AbsAxes:    axes,
+		MscEvents:  []uint16{MscTimestamp},
+		Properties: []uint16{PropAccelerometer},
+	}
+}
+
+func (vSensor vMotionSensor) Update(sample MotionSample) error {
+	accel := func(value float64) int32 {
+		return toSensorUnits(value/standardGravity, vSensor.config.AccelRange, vSensor.config.AccelResolution)
+	}
+	gyro := func(value float64) int32 {
+		return toSensorUnits(value, vSensor.config.GyroRange, vSensor.config.GyroResolution)
+	}
+
+	timestamp := sample.Timestamp
+	if timestamp == 0 {
+		timestamp = time.Since(vSensor.created)
+	}
+
+	frame := newFrame(vSensor.deviceFile)
+	frame.Emit(evAbs, AbsX, accel(sample.AccelX))
+	frame.Emit(evAbs, AbsY, accel(sample.AccelY))
+	frame.Emit(evAbs, AbsZ, accel(sample.AccelZ))
+	frame.Emit(evAbs, AbsRX, gyro(sample.GyroX))
+	frame.Emit(evAbs, AbsRY, gyro(sample.GyroY))
+	frame.Emit(evAbs, AbsRZ, gyro(sample.GyroZ))
+	frame.Emit(evMsc, MscTimestamp, int32(uint32(timestamp/time.Microsecond)))
+	err := frame.Flush()
+	if err != nil {
+		return fmt.Errorf("failed to send motion sensor event: %v", err)
+	}
+	return nil
+}
+
+// toSensorUnits maps a physical value onto the units of an axis with the given range and resolution.
+func toSensorUnits(value float64, max int32, resolution int32) int32 {
+	if value > float64(max) {
+		value = float64(max)
+	} else if value < -float64(max) {
+		value = -float64(max)
+	}
+	return int32(math.Round(value * float64(resolution)))
+}
+
+func (vSensor vMotionSensor) FetchSyspath() (string, error) {
+	return fetchSyspath(vSensor.deviceFile)
+}
+
+// Emit will send a single raw event to the device, immediately followed by a SYN_REPORT.
+func (vSensor vMotionSensor) Emit(evType uint16, code uint16, value int32) error {
+	return emitEvent(vSensor.deviceFile, evType, code, value)
+}
+
+// NewFrame will create an empty frame that may be used to send multiple events at once.
+func (vSensor vMotionSensor) NewFrame() *Frame {
+	return newFrame(vSensor.deviceFile)
+}
+
+// SetEventHandler registers a handler that is invoked for every event sent to the device.
+func (vSensor vMotionSensor) SetEventHandler(handler EventHandler) {
+	vSensor.reader.setHandler(handler)
+}
+
+// ReleaseAll has no effect, since motion sensors have neither keys nor buttons.
+func (vSensor vMotionSensor) ReleaseAll() error {
+	return releaseAll(vSensor.deviceFile)
+}
+
+func (vSensor vMotionSensor) Close() error {
+	return closeDevice(vSensor.deviceFile)
+}
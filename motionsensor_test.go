@@ -0,0 +1,88 @@
+package uinput
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMotionSensorRegistersSensorCapabilities(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	sensor, err := CreateMotionSensor(fake.Path(), []byte("Test Sensor"), MotionSensorConfig{})
+	if err != nil {
+		t.Fatalf("Failed to create the motion sensor. Last error was: %s\n", err)
+	}
+	defer sensor.Close()
+	dev := fake.Device("Test Sensor")
+
+	if !dev.HasProperty(PropAccelerometer) {
+		t.Fatalf("Expected the motion sensor to declare INPUT_PROP_ACCELEROMETER")
+	}
+	dev.ExpectCodes(t, EvAbs, AbsX, AbsY, AbsZ, AbsRX, AbsRY, AbsRZ)
+	dev.ExpectCodes(t, EvMsc, MscTimestamp)
+	dev.ExpectCodes(t, EvKey)
+
+	axis, _ := dev.Axis(AbsZ)
+	if axis.Min != -32768 || axis.Max != 32768 || axis.Resolution != 8192 {
+		t.Fatalf("Expected the accelerometer to range from -4g to 4g with 8192 units per g, but got %+v", axis)
+	}
+	axis, _ = dev.Axis(AbsRX)
+	if axis.Min != -2048*1024 || axis.Max != 2048*1024 || axis.Resolution != 1024 {
+		t.Fatalf("Expected the gyroscope to range from -2048 to 2048 deg/s with 1024 units per deg/s, but got %+v", axis)
+	}
+}
+
+func TestMotionSensorReportsPhysicalUnits(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	sensor, err := CreateMotionSensor(fake.Path(), []byte("Test Sensor"), MotionSensorConfig{})
+	if err != nil {
+		t.Fatalf("Failed to create the motion sensor. Last error was: %s\n", err)
+	}
+	defer sensor.Close()
+
+	// lying flat while turning, the accelerometer only measures gravity
+	err = sensor.Update(MotionSample{AccelZ: standardGravity, GyroY: -90, GyroZ: 5000, Timestamp: 1500 * time.Microsecond})
+	if err != nil {
+		t.Fatalf("Failed to update the motion sensor. Last error was: %s\n", err)
+	}
+	fake.Device("Test Sensor").ExpectEvents(t,
+		Event{Type: EvAbs, Code: AbsX, Value: 0},
+		Event{Type: EvAbs, Code: AbsY, Value: 0},
+		Event{Type: EvAbs, Code: AbsZ, Value: 8192},
+		Event{Type: EvAbs, Code: AbsRX, Value: 0},
+		Event{Type: EvAbs, Code: AbsRY, Value: -90 * 1024},
+		Event{Type: EvAbs, Code: AbsRZ, Value: 2048 * 1024},
+		Event{Type: EvMsc, Code: MscTimestamp, Value: 1500},
+		Event{Type: EvSyn, Code: SynReport})
+}
+
+func TestMotionSensorSharesIdentityOfGamepad(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	gamepad, err := CreateGamepadFromProfile(fake.Path(), ProfileDualShock4, nil, WithPhys("usb-0000:00:14.0-3/input3"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+	defer gamepad.Close()
+
+	sensor, err := CreateMotionSensorForGamepad(fake.Path(), gamepad, MotionSensorConfig{})
+	if err != nil {
+		t.Fatalf("Failed to create the motion sensor. Last error was: %s\n", err)
+	}
+	defer sensor.Close()
+
+	dev := fake.Device("Sony Interactive Entertainment Wireless Controller Motion Sensors")
+	if dev == nil {
+		t.Fatalf("Expected the motion sensor to be named after the gamepad")
+	}
+	if dev.ID() != ProfileDualShock4.ID {
+		t.Fatalf("Expected: %+v\nActual: %+v", ProfileDualShock4.ID, dev.ID())
+	}
+	if dev.Phys() != "usb-0000:00:14.0-3/input3" {
+		t.Fatalf("Expected: %s\nActual: %s", "usb-0000:00:14.0-3/input3", dev.Phys())
+	}
+}
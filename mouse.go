@@ -3,7 +3,6 @@ package uinput
 import (
 	"fmt"
 	"io"
-	"os"
 	"syscall"
 )
 
@@ -57,23 +56,46 @@ type Mouse interface {
 	// MiddleRelease will simulate the release of the middle mouse button.
 	MiddleRelease() error
 
-	// Wheel will simulate a wheel movement.
+	// ButtonClick will issue a click of the given button (ButtonLeft, ButtonRight, ButtonMiddle, ButtonSide,
+	// ButtonExtra, ButtonForward, ButtonBack or ButtonTask).
+	ButtonClick(button int) error
+
+	// ButtonPress will simulate the press of the given button. Note that the button will not be released until
+	// ButtonRelease is invoked.
+	ButtonPress(button int) error
+
+	// ButtonRelease will simulate the release of the given button.
+	ButtonRelease(button int) error
+
+	// Wheel will simulate a wheel movement by the given number of detents.
 	Wheel(horizontal bool, delta int32) error
 
+	// WheelHiRes will simulate a smooth wheel movement, given in fractions of 1/120 of a detent (e.g. 60 is half a
+	// detent). Movements are accumulated, so that a legacy wheel event is sent whenever a whole detent is reached.
+	WheelHiRes(horizontal bool, delta int32) error
+
 	// FetchSysPath will return the syspath to the device file.
 	FetchSyspath() (string, error)
 
+	EventEmitter
+
+	EventReceiver
+
+	Releaser
+
 	io.Closer
 }
 
 type vMouse struct {
 	name       []byte
-	deviceFile *os.File
+	deviceFile uinputFile
+	reader     *eventReader
+	wheel      *wheelState
 }
 
 // CreateMouse will create a new mouse input device. A mouse is a device that allows relative input.
 // Relative input means that all changes to the x and y coordinates of the mouse pointer will be
-func CreateMouse(path string, name []byte) (Mouse, error) {
+func CreateMouse(path string, name []byte, options ...DeviceOption) (Mouse, error) {
 	err := validateDevicePath(path)
 	if err != nil {
 		return nil, err
@@ -83,12 +105,12 @@ func CreateMouse(path string, name []byte) (Mouse, error) {
 		return nil, err
 	}
 
-	fd, err := createMouse(path, name)
+	fd, err := createMouse(path, name, newDeviceOptions(options))
 	if err != nil {
 		return nil, err
 	}
 
-	return vMouse{name: name, deviceFile: fd}, nil
+	return vMouse{name: name, deviceFile: fd, reader: startEventReader(fd, nil), wheel: &wheelState{}}, nil
 }
 
 // MoveLeft will move the cursor left by the number of pixel specified.
@@ -199,13 +221,49 @@ func (vRel vMouse) MiddleRelease() error {
 	return sendBtnEvent(vRel.deviceFile, []int{evMouseBtnMiddle}, btnStateReleased)
 }
 
-// Wheel will simulate a wheel movement.
+// ButtonClick will issue a click of the given button.
+func (vRel vMouse) ButtonClick(button int) error {
+	return clickMouseButton(vRel.deviceFile, button)
+}
+
+// ButtonPress will simulate the press of the given button.
+func (vRel vMouse) ButtonPress(button int) error {
+	return sendMouseButtonEvent(vRel.deviceFile, button, btnStatePressed)
+}
+
+// ButtonRelease will simulate the release of the given button.
+func (vRel vMouse) ButtonRelease(button int) error {
+	return sendMouseButtonEvent(vRel.deviceFile, button, btnStateReleased)
+}
+
+// Wheel will simulate a wheel movement by the given number of detents.
 func (vRel vMouse) Wheel(horizontal bool, delta int32) error {
-	w := relWheel
-	if horizontal {
-		w = relHWheel
-	}
-	return sendRelEvent(vRel.deviceFile, uint16(w), delta)
+	return sendWheelEvent(vRel.deviceFile, horizontal, delta)
+}
+
+// WheelHiRes will simulate a smooth wheel movement, given in fractions of 1/120 of a detent.
+func (vRel vMouse) WheelHiRes(horizontal bool, delta int32) error {
+	return vRel.wheel.scroll(vRel.deviceFile, horizontal, delta)
+}
+
+// Emit will send a single raw event to the device, immediately followed by a SYN_REPORT.
+func (vRel vMouse) Emit(evType uint16, code uint16, value int32) error {
+	return emitEvent(vRel.deviceFile, evType, code, value)
+}
+
+// NewFrame will create an empty frame that may be used to send multiple events at once.
+func (vRel vMouse) NewFrame() *Frame {
+	return newFrame(vRel.deviceFile)
+}
+
+// SetEventHandler registers a handler that is invoked for every event sent to the device.
+func (vRel vMouse) SetEventHandler(handler EventHandler) {
+	vRel.reader.setHandler(handler)
+}
+
+// ReleaseAll will release all keys, buttons and touch contacts that are currently held down.
+func (vRel vMouse) ReleaseAll() error {
+	return releaseAll(vRel.deviceFile)
 }
 
 // Close closes the device and releases the device.
@@ -213,7 +271,7 @@ func (vRel vMouse) Close() error {
 	return closeDevice(vRel.deviceFile)
 }
 
-func createMouse(path string, name []byte) (fd *os.File, err error) {
+func createMouse(path string, name []byte, options deviceOptions) (fd uinputFile, err error) {
 	deviceFile, err := createDeviceFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("could not create relative axis input device: %v", err)
@@ -225,8 +283,8 @@ func createMouse(path string, name []byte) (fd *os.File, err error) {
 		return nil, fmt.Errorf("failed to register key device: %v", err)
 	}
 
-	// register button events (in order to enable left, right and middle click)
-	for _, event := range []int{evMouseBtnLeft, evMouseBtnRight, evMouseBtnMiddle} {
+	// register button events (in order to enable left, right and middle click, as well as the side buttons)
+	for _, event := range mouseButtons {
 		err = ioctl(deviceFile, uiSetKeyBit, uintptr(event))
 		if err != nil {
 			deviceFile.Close()
@@ -241,7 +299,7 @@ func createMouse(path string, name []byte) (fd *os.File, err error) {
 	}
 
 	// register relative events
-	for _, event := range []int{relX, relY, relWheel, relHWheel} {
+	for _, event := range []int{relX, relY, relWheel, relHWheel, relWheelHiRes, relHWheelHiRes} {
 		err = ioctl(deviceFile, uiSetRelBit, uintptr(event))
 		if err != nil {
 			deviceFile.Close()
@@ -250,16 +308,18 @@ func createMouse(path string, name []byte) (fd *os.File, err error) {
 	}
 
 	return createUsbDevice(deviceFile,
-		uinputUserDev{
+		uinputSetup{
 			Name: toUinputName(name),
 			ID: inputID{
 				Bustype: busUsb,
 				Vendor:  0x4711,
 				Product: 0x0816,
-				Version: 1}})
+				Version: 1}},
+		nil,
+		options)
 }
 
-func sendRelEvent(deviceFile *os.File, eventCode uint16, pixel int32) error {
+func sendRelEvent(deviceFile uinputFile, eventCode uint16, pixel int32) error {
 	iev := inputEvent{
 		Time:  syscall.Timeval{Sec: 0, Usec: 0},
 		Type:  evRel,
@@ -279,6 +339,42 @@ func sendRelEvent(deviceFile *os.File, eventCode uint16, pixel int32) error {
 	return syncEvents(deviceFile)
 }
 
+// buttons supported by Mouse and MouseAbs
+var mouseButtons = []int{
+	ButtonLeft,
+	ButtonRight,
+	ButtonMiddle,
+	ButtonSide,
+	ButtonExtra,
+	ButtonForward,
+	ButtonBack,
+	ButtonTask,
+}
+
+func isMouseButton(button int) bool {
+	for _, b := range mouseButtons {
+		if b == button {
+			return true
+		}
+	}
+	return false
+}
+
+func clickMouseButton(deviceFile uinputFile, button int) error {
+	err := sendMouseButtonEvent(deviceFile, button, btnStatePressed)
+	if err != nil {
+		return fmt.Errorf("failed to issue the click event: %v", err)
+	}
+	return sendMouseButtonEvent(deviceFile, button, btnStateReleased)
+}
+
+func sendMouseButtonEvent(deviceFile uinputFile, button int, btnState int) error {
+	if !isMouseButton(button) {
+		return fmt.Errorf("button %d is not supported", button)
+	}
+	return sendBtnEvent(deviceFile, []int{button}, btnState)
+}
+
 func assertNotNegative(val int32) error {
 	if val < 0 {
 		return fmt.Errorf("%v is out of range. Expected a positive or zero value", val)
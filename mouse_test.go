@@ -440,3 +440,85 @@ func TestMouseSyspath(t *testing.T) {
 	}
 	t.Logf("Syspath: %s", sysPath)
 }
+
+func TestMouseSideButtons(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	relDev, err := CreateMouse(fake.Path(), []byte("Test Basic Mouse"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual mouse. Last error was: %s\n", err)
+	}
+	defer relDev.Close()
+	dev := fake.Device("Test Basic Mouse")
+	dev.ExpectCodes(t, EvKey, ButtonLeft, ButtonRight, ButtonMiddle, ButtonSide, ButtonExtra, ButtonForward,
+		ButtonBack, ButtonTask)
+
+	err = relDev.ButtonClick(ButtonBack)
+	if err != nil {
+		t.Fatalf("Failed to click the back button. Last error was: %s\n", err)
+	}
+	err = relDev.ButtonPress(ButtonForward)
+	if err != nil {
+		t.Fatalf("Failed to press the forward button. Last error was: %s\n", err)
+	}
+	err = relDev.ButtonRelease(ButtonForward)
+	if err != nil {
+		t.Fatalf("Failed to release the forward button. Last error was: %s\n", err)
+	}
+	dev.ExpectEvents(t,
+		Event{Type: EvKey, Code: ButtonBack, Value: 1},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvKey, Code: ButtonBack, Value: 0},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvKey, Code: ButtonForward, Value: 1},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvKey, Code: ButtonForward, Value: 0},
+		Event{Type: EvSyn, Code: SynReport})
+}
+
+func TestMouseButtonFailsOnUnsupportedButton(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	relDev, err := CreateMouse(fake.Path(), []byte("Test Basic Mouse"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual mouse. Last error was: %s\n", err)
+	}
+	defer relDev.Close()
+
+	err = relDev.ButtonPress(ButtonSouth)
+	if err == nil {
+		t.Fatalf("Expected ButtonPress to fail, but no error was returned.")
+	}
+	fake.Device("Test Basic Mouse").ExpectNoEvents(t)
+}
+
+func TestMouseWithPointingStickProperty(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	relDev, err := CreateMouse(fake.Path(), []byte("Test TrackPoint"), WithProperties(PropPointer, PropPointingStick))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual mouse. Last error was: %s\n", err)
+	}
+	defer relDev.Close()
+
+	dev := fake.Device("Test TrackPoint")
+	if !dev.HasProperty(PropPointer) || !dev.HasProperty(PropPointingStick) || dev.HasProperty(PropDirect) {
+		t.Fatalf("Expected the mouse to declare INPUT_PROP_POINTER and INPUT_PROP_POINTING_STICK only")
+	}
+}
+
+func TestMouseCreationFailsForInvalidProperty(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	_, err := CreateMouse(fake.Path(), []byte("Test Mouse"), WithProperties(0x20))
+	if err == nil {
+		t.Fatalf("Expected mouse creation to fail due to an invalid input property")
+	}
+	if fake.Device("Test Mouse") != nil {
+		t.Fatalf("Expected the device not to be created")
+	}
+}
@@ -3,8 +3,6 @@ package uinput
 import (
 	"fmt"
 	"io"
-	"os"
-	"syscall"
 )
 
 // A MouseAbs is a device that will trigger an absolute change event.
@@ -43,22 +41,45 @@ type MouseAbs interface {
 	// MiddleRelease will simulate the release of the middle mouse button.
 	MiddleRelease() error
 
-	// Wheel will simulate a wheel movement.
+	// ButtonClick will issue a click of the given button (ButtonLeft, ButtonRight, ButtonMiddle, ButtonSide,
+	// ButtonExtra, ButtonForward, ButtonBack or ButtonTask).
+	ButtonClick(button int) error
+
+	// ButtonPress will simulate the press of the given button. Note that the button will not be released until
+	// ButtonRelease is invoked.
+	ButtonPress(button int) error
+
+	// ButtonRelease will simulate the release of the given button.
+	ButtonRelease(button int) error
+
+	// Wheel will simulate a wheel movement by the given number of detents.
 	Wheel(horizontal bool, delta int32) error
 
+	// WheelHiRes will simulate a smooth wheel movement, given in fractions of 1/120 of a detent (e.g. 60 is half a
+	// detent). Movements are accumulated, so that a legacy wheel event is sent whenever a whole detent is reached.
+	WheelHiRes(horizontal bool, delta int32) error
+
 	// FetchSysPath will return the syspath to the device file.
 	FetchSyspath() (string, error)
 
+	EventEmitter
+
+	EventReceiver
+
+	Releaser
+
 	io.Closer
 }
 
 type vMouseAbs struct {
 	name       []byte
-	deviceFile *os.File
+	deviceFile uinputFile
+	reader     *eventReader
+	wheel      *wheelState
 }
 
 // CreateMouseAbs will create a new mouse input device. A mouseAbs is a device that allows absolute input.
-func CreateMouseAbs(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32) (MouseAbs, error) {
+func CreateMouseAbs(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32, options ...DeviceOption) (MouseAbs, error) {
 	err := validateDevicePath(path)
 	if err != nil {
 		return nil, err
@@ -68,12 +89,12 @@ func CreateMouseAbs(path string, name []byte, minX int32, maxX int32, minY int32
 		return nil, err
 	}
 
-	fd, err := createMouseAbs(path, name, minX, maxX, minY, maxY)
+	fd, err := createMouseAbs(path, name, minX, maxX, minY, maxY, newDeviceOptions(options))
 	if err != nil {
 		return nil, err
 	}
 
-	return vMouseAbs{name: name, deviceFile: fd}, nil
+	return vMouseAbs{name: name, deviceFile: fd, reader: startEventReader(fd, nil), wheel: &wheelState{}}, nil
 }
 
 // MoveTo will move the cursor to the specified position on the screen
@@ -144,13 +165,49 @@ func (vAbs vMouseAbs) MiddleRelease() error {
 	return sendBtnEvent(vAbs.deviceFile, []int{evMouseBtnMiddle}, btnStateReleased)
 }
 
-// Wheel will simulate a wheel movement.
+// ButtonClick will issue a click of the given button.
+func (vAbs vMouseAbs) ButtonClick(button int) error {
+	return clickMouseButton(vAbs.deviceFile, button)
+}
+
+// ButtonPress will simulate the press of the given button.
+func (vAbs vMouseAbs) ButtonPress(button int) error {
+	return sendMouseButtonEvent(vAbs.deviceFile, button, btnStatePressed)
+}
+
+// ButtonRelease will simulate the release of the given button.
+func (vAbs vMouseAbs) ButtonRelease(button int) error {
+	return sendMouseButtonEvent(vAbs.deviceFile, button, btnStateReleased)
+}
+
+// Wheel will simulate a wheel movement by the given number of detents.
 func (vAbs vMouseAbs) Wheel(horizontal bool, delta int32) error {
-	w := relWheel
-	if horizontal {
-		w = relHWheel
-	}
-	return vAbs.sendRelEvent(uint16(w), delta)
+	return sendWheelEvent(vAbs.deviceFile, horizontal, delta)
+}
+
+// WheelHiRes will simulate a smooth wheel movement, given in fractions of 1/120 of a detent.
+func (vAbs vMouseAbs) WheelHiRes(horizontal bool, delta int32) error {
+	return vAbs.wheel.scroll(vAbs.deviceFile, horizontal, delta)
+}
+
+// Emit will send a single raw event to the device, immediately followed by a SYN_REPORT.
+func (vAbs vMouseAbs) Emit(evType uint16, code uint16, value int32) error {
+	return emitEvent(vAbs.deviceFile, evType, code, value)
+}
+
+// NewFrame will create an empty frame that may be used to send multiple events at once.
+func (vAbs vMouseAbs) NewFrame() *Frame {
+	return newFrame(vAbs.deviceFile)
+}
+
+// SetEventHandler registers a handler that is invoked for every event sent to the device.
+func (vAbs vMouseAbs) SetEventHandler(handler EventHandler) {
+	vAbs.reader.setHandler(handler)
+}
+
+// ReleaseAll will release all keys, buttons and touch contacts that are currently held down.
+func (vAbs vMouseAbs) ReleaseAll() error {
+	return releaseAll(vAbs.deviceFile)
 }
 
 // Close closes the device and releases the device.
@@ -158,7 +215,7 @@ func (vAbs vMouseAbs) Close() error {
 	return closeDevice(vAbs.deviceFile)
 }
 
-func createMouseAbs(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32) (fd *os.File, err error) {
+func createMouseAbs(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32, options deviceOptions) (fd uinputFile, err error) {
 	deviceFile, err := createDeviceFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("could not create absolute axis input device: %v", err)
@@ -170,8 +227,8 @@ func createMouseAbs(path string, name []byte, minX int32, maxX int32, minY int32
 		return nil, fmt.Errorf("failed to register key device: %v", err)
 	}
 
-	// register button events (in order to enable left, right and middle click)
-	for _, event := range []int{evMouseBtnLeft, evMouseBtnRight, evMouseBtnMiddle} {
+	// register button events (in order to enable left, right and middle click, as well as the side buttons)
+	for _, event := range mouseButtons {
 		err = ioctl(deviceFile, uiSetKeyBit, uintptr(event))
 		if err != nil {
 			deviceFile.Close()
@@ -186,7 +243,7 @@ func createMouseAbs(path string, name []byte, minX int32, maxX int32, minY int32
 	}
 
 	// register relative events
-	for _, event := range []int{relWheel, relHWheel} {
+	for _, event := range []int{relWheel, relHWheel, relWheelHiRes, relHWheelHiRes} {
 		err = ioctl(deviceFile, uiSetRelBit, uintptr(event))
 		if err != nil {
 			deviceFile.Close()
@@ -209,24 +266,19 @@ func createMouseAbs(path string, name []byte, minX int32, maxX int32, minY int32
 		}
 	}
 
-	var absMin [absSize]int32
-	absMin[absX] = minX
-	absMin[absY] = minY
-
-	var absMax [absSize]int32
-	absMax[absX] = maxX
-	absMax[absY] = maxY
-
 	return createUsbDevice(deviceFile,
-		uinputUserDev{
+		uinputSetup{
 			Name: toUinputName(name),
 			ID: inputID{
 				Bustype: busUsb,
 				Vendor:  0x4711,
 				Product: 0x0816,
-				Version: 1},
-			Absmin: absMin,
-			Absmax: absMax})
+				Version: 1}},
+		[]uinputAbsSetup{
+			absAxis(absX, minX, maxX),
+			absAxis(absY, minY, maxY),
+		},
+		options)
 }
 
 func (vAbs vMouseAbs) sendAbsEvent(xPos int32, yPos int32) error { // TODO: Perhaps move this to a more generic function? This conflicts with the gamepad ABS events which only have one value.
@@ -262,26 +314,6 @@ func (vAbs vMouseAbs) sendAbsEvent(xPos int32, yPos int32) error { // TODO: Perh
 	return syncEvents(vAbs.deviceFile)
 }
 
-func (vAbs vMouseAbs) sendRelEvent(eventCode uint16, pixel int32) error {
-	iev := inputEvent{
-		Time:  syscall.Timeval{Sec: 0, Usec: 0},
-		Type:  evRel,
-		Code:  eventCode,
-		Value: pixel}
-
-	buf, err := inputEventToBuffer(iev)
-	if err != nil {
-		return fmt.Errorf("writing abs event failed: %v", err)
-	}
-
-	_, err = vAbs.deviceFile.Write(buf)
-	if err != nil {
-		return fmt.Errorf("failed to write rel event to device file: %v", err)
-	}
-
-	return syncEvents(vAbs.deviceFile)
-}
-
 func (vAbs vMouseAbs) FetchSyspath() (string, error) {
 	return fetchSyspath(vAbs.deviceFile)
 }
@@ -319,3 +319,32 @@ func TestMouseAbsSyspath(t *testing.T) {
 	}
 	t.Logf("Syspath: %s", sysPath)
 }
+
+func TestMouseAbsSideButtons(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	absDev, err := CreateMouseAbs(fake.Path(), []byte("Test Basic Mouse"), 0, 1024, 0, 768)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual mouse. Last error was: %s\n", err)
+	}
+	defer absDev.Close()
+	dev := fake.Device("Test Basic Mouse")
+	dev.ExpectCodes(t, EvKey, ButtonLeft, ButtonRight, ButtonMiddle, ButtonSide, ButtonExtra, ButtonForward,
+		ButtonBack, ButtonTask)
+
+	err = absDev.ButtonClick(ButtonSide)
+	if err != nil {
+		t.Fatalf("Failed to click the side button. Last error was: %s\n", err)
+	}
+	dev.ExpectEvents(t,
+		Event{Type: EvKey, Code: ButtonSide, Value: 1},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvKey, Code: ButtonSide, Value: 0},
+		Event{Type: EvSyn, Code: SynReport})
+
+	err = absDev.ButtonRelease(KeyA)
+	if err == nil {
+		t.Fatalf("Expected ButtonRelease to fail, but no error was returned.")
+	}
+}
@@ -3,7 +3,6 @@ package uinput
 import (
 	"fmt"
 	"io"
-	"os"
 )
 
 // MultiTouch is an input device that uses absolute axis events.
@@ -14,31 +13,46 @@ import (
 // Since MultiTouch uses absolute axis events, it is necessary to define the size
 // of the rectangle in which the contacs may move upon creation of the device.
 type MultiTouch interface {
-	//Gets all contacts which can then be manipulated
-	GetContacts() []multiTouchContact
+	// GetContacts will return all contacts (one per slot), which can then be manipulated.
+	GetContacts() []*Contact
+
+	// Pinch will move two fingers apart (zoom in) or towards each other (zoom out) around the given center.
+	Pinch(centerX int32, centerY int32, startDistance int32, endDistance int32, options GestureOptions) error
+
+	// Rotate will rotate two fingers around the given center by the given angle (in degrees, clockwise).
+	Rotate(centerX int32, centerY int32, radius int32, degrees float64, options GestureOptions) error
+
+	// Swipe will move the given number of fingers from one position to another.
+	Swipe(fingers int, fromX int32, fromY int32, toX int32, toY int32, options GestureOptions) error
+
+	// Tap will tap the surface at the given position using the given number of fingers.
+	Tap(fingers int, x int32, y int32, options GestureOptions) error
+
+	// LongPress will hold a single finger at the given position for the duration of the gesture.
+	LongPress(x int32, y int32, options GestureOptions) error
 
 	// FetchSyspath will return the syspath to the device file.
 	FetchSyspath() (string, error)
 
+	EventEmitter
+
+	EventReceiver
+
+	Releaser
+
 	io.Closer
 }
 
 type vMultiTouch struct {
+	touchSurface
 	name       []byte
-	deviceFile *os.File
-	contacts   []multiTouchContact
-}
-
-// The contact can be described as a finger contacting the surface of the MultiTouch device.
-type multiTouchContact struct {
-	multitouch  *vMultiTouch
-	slot        int32
-	tracking_id int32
+	deviceFile uinputFile
+	reader     *eventReader
 }
 
 // CreateMultiTouch will create a new multitouch device. Note that you will need to define the x and y-axis boundaries
 // (min and max) within which the contacs maybe moved around, as well as the maximum amount of contacts allowed.
-func CreateMultiTouch(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32, maxContacts int32) (MultiTouch, error) {
+func CreateMultiTouch(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32, maxContacts int32, options ...DeviceOption) (MultiTouch, error) {
 	err := validateDevicePath(path)
 	if err != nil {
 		return nil, err
@@ -47,34 +61,65 @@ func CreateMultiTouch(path string, name []byte, minX int32, maxX int32, minY int
 	if err != nil {
 		return nil, err
 	}
+	if maxContacts < 1 {
+		return nil, fmt.Errorf("maximum number of contacts must be at least 1, but is %d", maxContacts)
+	}
 
-	fd, err := createMultiTouch(path, name, minX, maxX, minY, maxY, maxContacts)
+	fd, err := createMultiTouch(path, name, minX, maxX, minY, maxY, maxContacts, newDeviceOptions(options, PropDirect))
 	if err != nil {
 		return nil, err
 	}
 
-	var multitouch vMultiTouch = vMultiTouch{name: name, deviceFile: fd}
-
-	for i := int32(0); i < maxContacts; i++ {
-		multitouch.contacts = append(multitouch.contacts, multiTouchContact{slot: i, multitouch: &multitouch})
-	}
-
-	return multitouch, nil
+	return vMultiTouch{
+		touchSurface: touchSurface{
+			contacts: newContactState(fd, maxContacts),
+			minX:     minX,
+			maxX:     maxX,
+			minY:     minY,
+			maxY:     maxY},
+		name:       name,
+		deviceFile: fd,
+		reader:     startEventReader(fd, nil)}, nil
 }
 
-func (vMulti vMultiTouch) GetContacts() []multiTouchContact {
-	return vMulti.contacts
+func (vMulti vMultiTouch) GetContacts() []*Contact {
+	return vMulti.contacts.all()
 }
 
 func (vMulti vMultiTouch) FetchSyspath() (string, error) {
 	return fetchSyspath(vMulti.deviceFile)
 }
 
+// Emit will send a single raw event to the device, immediately followed by a SYN_REPORT.
+func (vMulti vMultiTouch) Emit(evType uint16, code uint16, value int32) error {
+	return emitEvent(vMulti.deviceFile, evType, code, value)
+}
+
+// NewFrame will create an empty frame that may be used to send multiple events at once.
+func (vMulti vMultiTouch) NewFrame() *Frame {
+	return newFrame(vMulti.deviceFile)
+}
+
+// SetEventHandler registers a handler that is invoked for every event sent to the device.
+func (vMulti vMultiTouch) SetEventHandler(handler EventHandler) {
+	vMulti.reader.setHandler(handler)
+}
+
+// ReleaseAll will lift all contacts and release all buttons that are currently held down.
+func (vMulti vMultiTouch) ReleaseAll() error {
+	err := vMulti.contacts.releaseAll()
+	if err != nil {
+		return err
+	}
+	return releaseAll(vMulti.deviceFile)
+}
+
 func (vMulti vMultiTouch) Close() error {
+	_ = vMulti.contacts.releaseAll()
 	return closeDevice(vMulti.deviceFile)
 }
 
-func createMultiTouch(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32, maxContacts int32) (fd *os.File, err error) {
+func createMultiTouch(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32, maxContacts int32, options deviceOptions) (fd uinputFile, err error) {
 	deviceFile, err := createDeviceFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("could not create absolute axis input device: %v", err)
@@ -86,7 +131,7 @@ func createMultiTouch(path string, name []byte, minX int32, maxX int32, minY int
 		return nil, fmt.Errorf("failed to register key device: %v", err)
 	}
 
-	for _, event := range []int{evBtnTouch} {
+	for _, event := range append([]uint16{evBtnTouch}, fingerTools...) {
 		err = ioctl(deviceFile, uiSetKeyBit, uintptr(event))
 		if err != nil {
 			_ = deviceFile.Close()
@@ -113,91 +158,19 @@ func createMultiTouch(path string, name []byte, minX int32, maxX int32, minY int
 		}
 	}
 
-	var absMin [absSize]int32
-	absMin[absMtPositionX] = minX
-	absMin[absMtPositionY] = minY
-	absMin[absMtTrackingId] = 0x00
-	absMin[absMtSlot] = 0x00
-
-	var absMax [absSize]int32
-	absMax[absMtPositionX] = maxX
-	absMax[absMtPositionY] = maxY
-	absMax[absMtTrackingId] = maxContacts
-	absMax[absMtSlot] = maxContacts
-
 	return createUsbDevice(deviceFile,
-		uinputUserDev{
+		uinputSetup{
 			Name: toUinputName(name),
 			ID: inputID{
 				Bustype: busUsb,
 				Vendor:  0x0,
 				Product: 0x0,
-				Version: 0},
-			Absmin: absMin,
-			Absmax: absMax})
-}
-
-// The contact will be held down at the coordinates specified
-func (c multiTouchContact) TouchDownAt(x int32, y int32) error {
-	var events []inputEvent
-
-	events = append(events, inputEvent{
-		Type:  evAbs,
-		Code:  absMtPositionX,
-		Value: x,
-	})
-
-	if x == 0 && y == 0 {
-		y--
-	}
-
-	events = append(events, inputEvent{
-		Type:  evAbs,
-		Code:  absMtPositionY,
-		Value: y,
-	})
-
-	c.tracking_id = c.slot
-
-	return c.sendAbsEvent(events)
-}
-
-// The contact will be raised off of the surface
-func (c multiTouchContact) TouchUp() error {
-	c.tracking_id = -1
-	return c.sendAbsEvent(nil)
-}
-
-func (c multiTouchContact) sendAbsEvent(events []inputEvent) error {
-	var ev []inputEvent
-
-	ev = append(ev, inputEvent{
-		Type:  evAbs,
-		Code:  absMtSlot,
-		Value: c.slot,
-	})
-
-	ev = append(ev, inputEvent{
-		Type:  evAbs,
-		Code:  absMtTrackingId,
-		Value: c.tracking_id,
-	})
-
-	if events != nil {
-		ev = append(ev, events...)
-	}
-
-	for _, iev := range ev {
-		buf, err := inputEventToBuffer(iev)
-		if err != nil {
-			return fmt.Errorf("writing abs event failed: %v", err)
-		}
-
-		_, err = c.multitouch.deviceFile.Write(buf)
-		if err != nil {
-			return fmt.Errorf("failed to write abs event to device file: %v", err)
-		}
-	}
-
-	return syncEvents(c.multitouch.deviceFile)
+				Version: 0}},
+		[]uinputAbsSetup{
+			absAxis(absMtSlot, 0, maxContacts-1),
+			absAxis(absMtTrackingId, 0, maxTrackingID),
+			absAxis(absMtPositionX, minX, maxX),
+			absAxis(absMtPositionY, minY, maxY),
+		},
+		options)
 }
@@ -223,3 +223,144 @@ func TestMultiTouchSyspath(t *testing.T) {
 
 	t.Logf("Syspath: %s", sysPath)
 }
+
+func TestMultiTouchContactLifecycle(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	dev, err := CreateMultiTouch(fake.Path(), []byte("MultiTouch"), 0, 1024, 0, 768, 2)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual touch pad. Last error was: %s\n", err)
+	}
+	defer dev.Close()
+	fd := fake.Device("MultiTouch")
+	fd.ExpectCodes(t, EvKey, ButtonTouch, ButtonToolFinger, ButtonToolDoubleTap, ButtonToolTripleTap,
+		ButtonToolQuadTap, ButtonToolQuintTap)
+
+	contacts := dev.GetContacts()
+	err = contacts[0].Down(10, 20)
+	if err != nil {
+		t.Fatalf("Failed to put contact 0 down. Last error was: %s\n", err)
+	}
+	err = contacts[1].Down(30, 40)
+	if err != nil {
+		t.Fatalf("Failed to put contact 1 down. Last error was: %s\n", err)
+	}
+	err = contacts[1].Move(30, 50)
+	if err != nil {
+		t.Fatalf("Failed to move contact 1. Last error was: %s\n", err)
+	}
+	err = contacts[0].Up()
+	if err != nil {
+		t.Fatalf("Failed to lift contact 0. Last error was: %s\n", err)
+	}
+	err = contacts[0].Down(5, 5)
+	if err != nil {
+		t.Fatalf("Failed to put contact 0 down. Last error was: %s\n", err)
+	}
+
+	fd.ExpectEvents(t,
+		Event{Type: EvAbs, Code: AbsMtTrackingID, Value: 0},
+		Event{Type: EvAbs, Code: AbsMtPositionX, Value: 10},
+		Event{Type: EvAbs, Code: AbsMtPositionY, Value: 20},
+		Event{Type: EvKey, Code: ButtonTouch, Value: 1},
+		Event{Type: EvKey, Code: ButtonToolFinger, Value: 1},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvAbs, Code: AbsMtSlot, Value: 1},
+		Event{Type: EvAbs, Code: AbsMtTrackingID, Value: 1},
+		Event{Type: EvAbs, Code: AbsMtPositionX, Value: 30},
+		Event{Type: EvAbs, Code: AbsMtPositionY, Value: 40},
+		Event{Type: EvKey, Code: ButtonToolFinger, Value: 0},
+		Event{Type: EvKey, Code: ButtonToolDoubleTap, Value: 1},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvAbs, Code: AbsMtPositionY, Value: 50},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvAbs, Code: AbsMtSlot, Value: 0},
+		Event{Type: EvAbs, Code: AbsMtTrackingID, Value: -1},
+		Event{Type: EvKey, Code: ButtonToolDoubleTap, Value: 0},
+		Event{Type: EvKey, Code: ButtonToolFinger, Value: 1},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvAbs, Code: AbsMtTrackingID, Value: 2},
+		Event{Type: EvAbs, Code: AbsMtPositionX, Value: 5},
+		Event{Type: EvAbs, Code: AbsMtPositionY, Value: 5},
+		Event{Type: EvKey, Code: ButtonToolFinger, Value: 0},
+		Event{Type: EvKey, Code: ButtonToolDoubleTap, Value: 1},
+		Event{Type: EvSyn, Code: SynReport})
+
+	if contacts[0].TrackingID() != 2 || !contacts[0].IsDown() {
+		t.Fatalf("Expected contact 0 to be down with tracking id 2, but got %d", contacts[0].TrackingID())
+	}
+	if x, y := contacts[1].Position(); x != 30 || y != 50 {
+		t.Fatalf("Expected contact 1 to be at [30, 50], but got [%d, %d]", x, y)
+	}
+
+	// moving to the current position must not send anything
+	err = contacts[1].Move(30, 50)
+	if err != nil {
+		t.Fatalf("Failed to move contact 1. Last error was: %s\n", err)
+	}
+	fd.ExpectNoEvents(t)
+
+	err = dev.ReleaseAll()
+	if err != nil {
+		t.Fatalf("Failed to release all contacts. Last error was: %s\n", err)
+	}
+	fd.ExpectEvents(t,
+		Event{Type: EvAbs, Code: AbsMtTrackingID, Value: -1},
+		Event{Type: EvAbs, Code: AbsMtSlot, Value: 1},
+		Event{Type: EvAbs, Code: AbsMtTrackingID, Value: -1},
+		Event{Type: EvKey, Code: ButtonTouch, Value: 0},
+		Event{Type: EvKey, Code: ButtonToolDoubleTap, Value: 0},
+		Event{Type: EvSyn, Code: SynReport})
+}
+
+func TestMultiTouchContactStateIsChecked(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	dev, err := CreateMultiTouch(fake.Path(), []byte("MultiTouch"), 0, 1024, 0, 768, 2)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual touch pad. Last error was: %s\n", err)
+	}
+	defer dev.Close()
+
+	contact := dev.GetContacts()[0]
+	if contact.Move(1, 1) == nil {
+		t.Fatalf("Expected moving a contact that is not down to fail")
+	}
+	if contact.Up() == nil {
+		t.Fatalf("Expected lifting a contact that is not down to fail")
+	}
+	err = contact.Down(1, 1)
+	if err != nil {
+		t.Fatalf("Failed to put contact down. Last error was: %s\n", err)
+	}
+	if contact.Down(1, 1) == nil {
+		t.Fatalf("Expected putting a contact down twice to fail")
+	}
+}
+
+func TestMultiTouchCreationFailsWithoutContacts(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	_, err := CreateMultiTouch(fake.Path(), []byte("MultiTouch"), 0, 1024, 0, 768, 0)
+	if err == nil {
+		t.Fatalf("Expected creation to fail, but no error was returned.")
+	}
+}
+
+func TestMultiTouchIsDirectByDefault(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	absDev, err := CreateMultiTouch(fake.Path(), []byte("Test MultiTouch"), 0, 1024, 0, 768, 3)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual multi touch device. Last error was: %s\n", err)
+	}
+	defer absDev.Close()
+
+	if !fake.Device("Test MultiTouch").HasProperty(PropDirect) {
+		t.Fatalf("Expected the multi touch device to declare INPUT_PROP_DIRECT")
+	}
+}
@@ -0,0 +1,124 @@
+package uinput
+
+import "fmt"
+
+// A DeviceOption customizes a device upon creation. Options may be passed to all Create functions.
+//
+// Note that there is no option to set the unique identifier (uniq) of a device, since uinput does not provide a way
+// to set it. Devices created using uinput always report an empty uniq.
+type DeviceOption func(*deviceOptions)
+
+// deviceOptions collects the settings of all options passed to a Create function.
+type deviceOptions struct {
+	properties []uint16
+	id         *InputID
+	phys       string
+	// threshold at which the analog triggers of a gamepad press the related buttons (0 disables the buttons)
+	triggerThreshold float32
+	// number of additional hats of a gamepad
+	hats int
+	// names of the options that were passed, but are only supported by gamepads
+	gamepadOnly []string
+	// whether the options are applied to a gamepad
+	gamepad bool
+}
+
+// WithID sets the bus type (see the Bus* constants in keycodes.go), vendor, product and version of the device. It
+// takes precedence over the identity passed to the Create function (if any), as well as the default identity of the
+// device type.
+func WithID(id InputID) DeviceOption {
+	return func(o *deviceOptions) {
+		o.id = &id
+	}
+}
+
+// WithPhys sets the physical path of the device (e.g. "usb-0000:00:14.0-1/input0"), which is otherwise empty.
+func WithPhys(phys string) DeviceOption {
+	return func(o *deviceOptions) {
+		o.phys = phys
+	}
+}
+
+// WithTriggerThreshold lets a gamepad press ButtonTriggerLeft and ButtonTriggerRight as soon as the related analog
+// trigger is pulled to the given threshold (0.0:1.0), and release them once it drops below again, like real controllers
+// do. Without this option, the analog triggers leave the buttons untouched. The option is only supported by gamepads,
+// the creation of other devices fails if it is passed.
+func WithTriggerThreshold(threshold float32) DeviceOption {
+	return func(o *deviceOptions) {
+		o.triggerThreshold = threshold
+		o.gamepadOnly = append(o.gamepadOnly, "WithTriggerThreshold")
+	}
+}
+
+// WithHats lets a gamepad register the given number of hats (up to 4), which are addressed by their index (see
+// HatMove). Gamepads have a single hat by default. The option is only supported by gamepads, the creation of other
+// devices fails if it is passed (joysticks take the number of hats from JoystickConfig instead).
+func WithHats(count int) DeviceOption {
+	return func(o *deviceOptions) {
+		o.hats = count
+		o.gamepadOnly = append(o.gamepadOnly, "WithHats")
+	}
+}
+
+// WithProperties adds the given input properties (see the Prop* constants in keycodes.go) to the device, in addition
+// to the default properties of its type (e.g. INPUT_PROP_DIRECT for MultiTouch and INPUT_PROP_POINTER for TouchPad).
+func WithProperties(properties ...uint16) DeviceOption {
+	return func(o *deviceOptions) {
+		o.properties = append(o.properties, properties...)
+	}
+}
+
+// newDeviceOptions will apply the given options on top of the default properties of a device type.
+func newDeviceOptions(options []DeviceOption, defaultProperties ...uint16) deviceOptions {
+	o := deviceOptions{properties: append([]uint16(nil), defaultProperties...)}
+	for _, option := range options {
+		if option != nil {
+			option(&o)
+		}
+	}
+	return o
+}
+
+// newGamepadOptions will apply the given options to a gamepad, which supports all options.
+func newGamepadOptions(options []DeviceOption) deviceOptions {
+	o := newDeviceOptions(options)
+	o.gamepad = true
+	return o
+}
+
+// validate will return an error if options were passed that the device does not support.
+func (o deviceOptions) validate() error {
+	if !o.gamepad && len(o.gamepadOnly) > 0 {
+		return fmt.Errorf("option %s is only supported by gamepads", o.gamepadOnly[0])
+	}
+	return nil
+}
+
+// apply will set the physical path of the device (UI_SET_PHYS) and override the identity in the setup, if requested.
+func (o deviceOptions) apply(deviceFile uinputFile, setup *uinputSetup) error {
+	if o.phys != "" {
+		err := ioctl(deviceFile, uiSetPhys, append([]byte(o.phys), 0))
+		if err != nil {
+			return fmt.Errorf("failed to set physical path: %v", err)
+		}
+	}
+	if o.id != nil {
+		setup.ID = inputID{
+			Bustype: o.id.Bustype,
+			Vendor:  o.id.Vendor,
+			Product: o.id.Product,
+			Version: o.id.Version}
+	}
+	return nil
+}
+
+// registerProperties will register the input properties of the device (UI_SET_PROPBIT).
+func registerProperties(deviceFile uinputFile, properties []uint16) error {
+	for _, property := range properties {
+		err := ioctl(deviceFile, uiSetPropBit, uintptr(property))
+		if err != nil {
+			return fmt.Errorf("failed to register input property %d: %v", property, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package uinput
+
+import (
+	"io"
+	"testing"
+)
+
+func TestDefaultIdentityIsPreserved(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	vk, err := CreateKeyboard(fake.Path(), []byte("Test Keyboard"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	defer vk.Close()
+
+	dev := fake.Device("Test Keyboard")
+	expected := InputID{Bustype: BusUsb, Vendor: 0x4711, Product: 0x0815, Version: 1}
+	if dev.ID() != expected {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, dev.ID())
+	}
+	if dev.Phys() != "" {
+		t.Fatalf("Expected no physical path, but got %q", dev.Phys())
+	}
+}
+
+func TestIdentityAndPhysMayBeSet(t *testing.T) {
+	name := []byte("Test Device")
+	constructors := map[string]func(path string, options ...DeviceOption) (io.Closer, error){
+		"Keyboard": func(path string, options ...DeviceOption) (io.Closer, error) {
+			return CreateKeyboard(path, name, options...)
+		},
+		"KeyboardWithLayout": func(path string, options ...DeviceOption) (io.Closer, error) {
+			return CreateKeyboardWithLayout(path, name, LayoutDE, options...)
+		},
+		"Mouse": func(path string, options ...DeviceOption) (io.Closer, error) {
+			return CreateMouse(path, name, options...)
+		},
+		"MouseAbs": func(path string, options ...DeviceOption) (io.Closer, error) {
+			return CreateMouseAbs(path, name, 0, 1024, 0, 768, options...)
+		},
+		"TouchPad": func(path string, options ...DeviceOption) (io.Closer, error) {
+			return CreateTouchPad(path, name, 0, 1024, 0, 768, options...)
+		},
+		"Dial": func(path string, options ...DeviceOption) (io.Closer, error) {
+			return CreateDial(path, name, options...)
+		},
+		"MultiTouch": func(path string, options ...DeviceOption) (io.Closer, error) {
+			return CreateMultiTouch(path, name, 0, 1024, 0, 768, 2, options...)
+		},
+		"PrecisionTouchPad": func(path string, options ...DeviceOption) (io.Closer, error) {
+			return CreatePrecisionTouchPad(path, name, PrecisionTouchPadConfig{Width: 1000, Height: 600, Resolution: 10}, options...)
+		},
+		"Tablet": func(path string, options ...DeviceOption) (io.Closer, error) {
+			return CreateTablet(path, name, TabletConfig{Width: 30000, Height: 20000, Resolution: 200}, options...)
+		},
+		"TabletPad": func(path string, options ...DeviceOption) (io.Closer, error) {
+			return CreateTabletPad(path, name, TabletPadConfig{}, options...)
+		},
+		"Device": func(path string, options ...DeviceOption) (io.Closer, error) {
+			return CreateDevice(path, name, DeviceSpec{Keys: []uint16{KeyA}}, options...)
+		},
+		"Gamepad": func(path string, options ...DeviceOption) (io.Closer, error) {
+			return CreateGamepad(path, name, 0x0fff, 0x0ff2, options...)
+		},
+		"GamepadWithAxes": func(path string, options ...DeviceOption) (io.Closer, error) {
+			return CreateGamepadWithAxes(path, name, 0x0fff, 0x0ff2, nil, options...)
+		},
+		"GamepadWithFF": func(path string, options ...DeviceOption) (io.Closer, error) {
+			return CreateGamepadWithFF(path, name, 0x0fff, 0x0ff2, nil, options...)
+		},
+		"GenericGamepad": func(path string, options ...DeviceOption) (io.Closer, error) {
+			return CreateGenericGamepad(path, BusUsb, name, 0x0fff, 0x0ff2, 1, []uint16{ButtonSouth}, []uint16{AbsX}, options...)
+		},
+		"GenericGamepadWithAxes": func(path string, options ...DeviceOption) (io.Closer, error) {
+			return CreateGenericGamepadWithAxes(path, BusUsb, name, 0x0fff, 0x0ff2, 1, []uint16{ButtonSouth},
+				[]AxisConfig{{Code: AbsX, Min: 0, Max: 255}}, options...)
+		},
+		"GamepadFromProfile": func(path string, options ...DeviceOption) (io.Closer, error) {
+			return CreateGamepadFromProfile(path, GamepadProfile{Name: string(name), Keys: xpadKeys, Axes: ProfileXbox360.Axes},
+				nil, options...)
+		},
+		"Joystick": func(path string, options ...DeviceOption) (io.Closer, error) {
+			return CreateJoystick(path, name, JoystickConfig{}, options...)
+		},
+		"MotionSensor": func(path string, options ...DeviceOption) (io.Closer, error) {
+			return CreateMotionSensor(path, name, MotionSensorConfig{}, options...)
+		},
+	}
+
+	id := InputID{Bustype: BusBluetooth, Vendor: 0x046d, Product: 0xb342, Version: 0x0111}
+	for constructor, create := range constructors {
+		fake := NewFake()
+		device, err := create(fake.Path(), WithID(id), WithPhys("usb-0000:00:14.0-1/input0"))
+		if err != nil {
+			t.Fatalf("Failed to create the device using %s. Last error was: %s\n", constructor, err)
+		}
+
+		dev := fake.Device(string(name))
+		if dev.ID() != id {
+			t.Errorf("%s ignores WithID\nExpected: %+v\nActual: %+v", constructor, id, dev.ID())
+		}
+		if dev.Phys() != "usb-0000:00:14.0-1/input0" {
+			t.Errorf("%s ignores WithPhys\nExpected: %s\nActual: %s", constructor, "usb-0000:00:14.0-1/input0", dev.Phys())
+		}
+		_ = device.Close()
+		fake.Close()
+	}
+}
+
+func TestIdentityOptionTakesPrecedence(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	id := InputID{Bustype: BusUsb, Vendor: 0x045e, Product: 0x028e, Version: 0x0114}
+	gamepad, err := CreateGamepad(fake.Path(), []byte("Test Gamepad"), 0x0fff, 0x0ff2, WithID(id))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+	defer gamepad.Close()
+
+	actual := fake.Device("Test Gamepad").ID()
+	if actual != id {
+		t.Fatalf("Expected: %+v\nActual: %+v", id, actual)
+	}
+}
+
+func TestOptionsApplyToGenericDevices(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	spec := DeviceSpec{ID: InputID{Bustype: BusUsb, Vendor: 1, Product: 2, Version: 3}, Keys: []uint16{KeyA}}
+	device, err := CreateDevice(fake.Path(), []byte("Test Device"), spec,
+		WithID(InputID{Bustype: BusVirtual, Vendor: 4, Product: 5, Version: 6}), WithProperties(PropPointer))
+	if err != nil {
+		t.Fatalf("Failed to create the device. Last error was: %s\n", err)
+	}
+	defer device.Close()
+
+	dev := fake.Device("Test Device")
+	expected := InputID{Bustype: BusVirtual, Vendor: 4, Product: 5, Version: 6}
+	if dev.ID() != expected {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, dev.ID())
+	}
+	if !dev.HasProperty(PropPointer) {
+		t.Fatalf("Expected the device to declare INPUT_PROP_POINTER")
+	}
+}
+
+func TestGamepadOptionsAreRejectedByOtherDevices(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	options := map[string]DeviceOption{
+		"WithTriggerThreshold": WithTriggerThreshold(0.5),
+		"WithHats":             WithHats(2),
+	}
+	for name, option := range options {
+		_, err := CreateKeyboard(fake.Path(), []byte("Test Keyboard"), option)
+		if err == nil {
+			t.Fatalf("Expected keyboard creation to fail due to option %s", name)
+		}
+		_, err = CreateJoystick(fake.Path(), []byte("Test Joystick"), JoystickConfig{}, option)
+		if err == nil {
+			t.Fatalf("Expected joystick creation to fail due to option %s", name)
+		}
+
+		gamepad, err := CreateGamepad(fake.Path(), []byte("Test Gamepad"), 0xDEAD, 0xBEEF, option)
+		if err != nil {
+			t.Fatalf("Failed to create the virtual gamepad with option %s. Last error was: %s\n", name, err)
+		}
+		_ = gamepad.Close()
+	}
+}
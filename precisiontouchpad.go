@@ -0,0 +1,289 @@
+package uinput
+
+import (
+	"fmt"
+	"io"
+)
+
+// defaults of a precision touch pad
+const (
+	defaultTouchPadSlots       = 5
+	defaultTouchPadMaxPressure = 255
+)
+
+// PrecisionTouchPadConfig describes the surface of a precision touch pad. The zero value of optional fields selects
+// sensible defaults.
+type PrecisionTouchPadConfig struct {
+	// Width and Height of the surface in device units. The x-axis ranges from 0 to Width, the y-axis from 0 to Height.
+	Width  int32
+	Height int32
+	// Resolution of both axes in units per millimeter. It is required, since libinput relies on the physical size of
+	// the surface for pointer acceleration, palm detection and gesture thresholds.
+	Resolution int32
+	// MaxPressure of a contact. Defaults to 255. Contacts that are put down without an explicit pressure use half of
+	// the maximum.
+	MaxPressure int32
+	// Slots is the maximum number of contacts. Defaults to 5.
+	Slots int32
+	// ButtonPad declares a clickpad, i.e. a touch pad without separate buttons that may be pressed as a whole (like
+	// the trackpads of Apple laptops). Clickpads only support the left button.
+	ButtonPad bool
+}
+
+// A Touch describes a single contact on the surface, as passed to SetTouches.
+type Touch struct {
+	// Slot of the contact (0 to Slots-1)
+	Slot int32
+	X    int32
+	Y    int32
+	// Pressure of the contact, 0 selects the default pressure
+	Pressure int32
+}
+
+// A PrecisionTouchPad is a multi touch device that is recognized as a touch pad by libinput. It follows the multi
+// touch protocol (type B), reports the physical size of its surface as well as the pressure of each contact and
+// emulates a single touch pointer for legacy clients. This allows forwarding the finger positions of a real touch pad
+// (like those of a remote client), letting libinput recognize scrolling, pinch and swipe gestures natively.
+type PrecisionTouchPad interface {
+	// GetContacts will return all contacts (one per slot), which can then be manipulated.
+	GetContacts() []*Contact
+
+	// SetTouches will update all contacts within a single frame: Contacts listed are put down (or moved) at the given
+	// position, all other contacts are lifted. Passing no touches lifts all contacts.
+	SetTouches(touches []Touch) error
+
+	// ButtonClick will press and release the given button (see ButtonLeft to ButtonMiddle in keycodes.go).
+	ButtonClick(button int) error
+
+	// ButtonPress will press the given button. The button will not be released until ButtonRelease is invoked.
+	ButtonPress(button int) error
+
+	// ButtonRelease will release the given button.
+	ButtonRelease(button int) error
+
+	// Pinch will move two fingers apart (zoom in) or towards each other (zoom out) around the given center.
+	Pinch(centerX int32, centerY int32, startDistance int32, endDistance int32, options GestureOptions) error
+
+	// Rotate will rotate two fingers around the given center by the given angle (in degrees, clockwise).
+	Rotate(centerX int32, centerY int32, radius int32, degrees float64, options GestureOptions) error
+
+	// Swipe will move the given number of fingers from one position to another.
+	Swipe(fingers int, fromX int32, fromY int32, toX int32, toY int32, options GestureOptions) error
+
+	// Tap will tap the surface at the given position using the given number of fingers.
+	Tap(fingers int, x int32, y int32, options GestureOptions) error
+
+	// LongPress will hold a single finger at the given position for the duration of the gesture.
+	LongPress(x int32, y int32, options GestureOptions) error
+
+	// FetchSyspath will return the syspath to the device file.
+	FetchSyspath() (string, error)
+
+	EventEmitter
+
+	EventReceiver
+
+	Releaser
+
+	io.Closer
+}
+
+type vPrecisionTouchPad struct {
+	touchSurface
+	name       []byte
+	deviceFile uinputFile
+	buttons    []int
+	reader     *eventReader
+}
+
+// CreatePrecisionTouchPad will create a new precision touch pad with the given surface.
+func CreatePrecisionTouchPad(path string, name []byte, config PrecisionTouchPadConfig, options ...DeviceOption) (PrecisionTouchPad, error) {
+	err := validateDevicePath(path)
+	if err != nil {
+		return nil, err
+	}
+	err = validateUinputName(name)
+	if err != nil {
+		return nil, err
+	}
+	config, err = config.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+
+	buttons := config.buttons()
+	fd, err := createDevice(path, name, config.spec(buttons), newDeviceOptions(options))
+	if err != nil {
+		return nil, err
+	}
+
+	contacts := newContactState(fd, config.Slots)
+	contacts.pressure = true
+	contacts.defaultPressure = config.MaxPressure / 2
+	contacts.emulatePointer = true
+
+	return vPrecisionTouchPad{
+		touchSurface: touchSurface{
+			contacts: contacts,
+			minX:     0,
+			maxX:     config.Width,
+			minY:     0,
+			maxY:     config.Height},
+		name:       name,
+		deviceFile: fd,
+		buttons:    buttons,
+		reader:     startEventReader(fd, nil)}, nil
+}
+
+func (config PrecisionTouchPadConfig) withDefaults() (PrecisionTouchPadConfig, error) {
+	if config.Width <= 0 || config.Height <= 0 {
+		return config, fmt.Errorf("width and height of the touch pad must be positive, but are %d and %d",
+			config.Width, config.Height)
+	}
+	if config.Resolution <= 0 {
+		return config, fmt.Errorf("resolution of the touch pad must be positive, but is %d", config.Resolution)
+	}
+	if config.MaxPressure < 0 || config.Slots < 0 {
+		return config, fmt.Errorf("maximum pressure and number of slots must not be negative")
+	}
+	if config.MaxPressure == 0 {
+		config.MaxPressure = defaultTouchPadMaxPressure
+	}
+	if config.Slots == 0 {
+		config.Slots = defaultTouchPadSlots
+	}
+	return config, nil
+}
+
+func (config PrecisionTouchPadConfig) buttons() []int {
+	if config.ButtonPad {
+		return []int{ButtonLeft}
+	}
+	return []int{ButtonLeft, ButtonRight, ButtonMiddle}
+}
+
+func (config PrecisionTouchPadConfig) spec(buttons []int) DeviceSpec {
+	var keys []uint16
+	for _, button := range buttons {
+		keys = append(keys, uint16(button))
+	}
+	keys = append(append(keys, ButtonTouch), fingerTools...)
+
+	properties := []uint16{PropPointer}
+	if config.ButtonPad {
+		properties = append(properties, PropButtonpad)
+	}
+
+	return DeviceSpec{
+		ID:   InputID{Bustype: BusUsb, Vendor: 0x4711, Product: 0x0819, Version: 1},
+		Keys: keys,
+		AbsAxes: []AxisConfig{
+			{Code: AbsX, Min: 0, Max: config.Width, Resolution: config.Resolution},
+			{Code: AbsY, Min: 0, Max: config.Height, Resolution: config.Resolution},
+			{Code: AbsPressure, Min: 0, Max: config.MaxPressure},
+			{Code: AbsMtSlot, Min: 0, Max: config.Slots - 1},
+			{Code: AbsMtTrackingID, Min: 0, Max: maxTrackingID},
+			{Code: AbsMtPositionX, Min: 0, Max: config.Width, Resolution: config.Resolution},
+			{Code: AbsMtPositionY, Min: 0, Max: config.Height, Resolution: config.Resolution},
+			{Code: AbsMtPressure, Min: 0, Max: config.MaxPressure},
+		},
+		Properties: properties,
+	}
+}
+
+func (vTouch vPrecisionTouchPad) GetContacts() []*Contact {
+	return vTouch.contacts.all()
+}
+
+func (vTouch vPrecisionTouchPad) SetTouches(touches []Touch) error {
+	contacts := vTouch.contacts.all()
+	touched := make(map[int32]bool, len(touches))
+	var updates []contactUpdate
+	for _, touch := range touches {
+		if touch.Slot < 0 || int(touch.Slot) >= len(contacts) {
+			return fmt.Errorf("failed to set touches. Slot %d is out of range", touch.Slot)
+		}
+		update := contactUpdate{contact: contacts[touch.Slot], action: contactDown, x: touch.X, y: touch.Y}
+		if contacts[touch.Slot].IsDown() {
+			update.action = contactMove
+		}
+		if touch.Pressure != 0 {
+			pressure := touch.Pressure
+			update.pressure = &pressure
+		}
+		touched[touch.Slot] = true
+		updates = append(updates, update)
+	}
+	for _, c := range contacts {
+		if !touched[c.Slot()] && c.IsDown() {
+			updates = append(updates, contactUpdate{contact: c, action: contactUp})
+		}
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+
+	err := vTouch.contacts.apply(updates...)
+	if err != nil {
+		return fmt.Errorf("failed to set touches: %v", err)
+	}
+	return nil
+}
+
+func (vTouch vPrecisionTouchPad) ButtonClick(button int) error {
+	err := vTouch.ButtonPress(button)
+	if err != nil {
+		return fmt.Errorf("failed to issue the click event: %v", err)
+	}
+	return vTouch.ButtonRelease(button)
+}
+
+func (vTouch vPrecisionTouchPad) ButtonPress(button int) error {
+	return vTouch.sendButtonEvent(button, btnStatePressed)
+}
+
+func (vTouch vPrecisionTouchPad) ButtonRelease(button int) error {
+	return vTouch.sendButtonEvent(button, btnStateReleased)
+}
+
+func (vTouch vPrecisionTouchPad) sendButtonEvent(button int, btnState int) error {
+	for _, b := range vTouch.buttons {
+		if b == button {
+			return sendBtnEvent(vTouch.deviceFile, []int{button}, btnState)
+		}
+	}
+	return fmt.Errorf("button %d is not supported", button)
+}
+
+func (vTouch vPrecisionTouchPad) FetchSyspath() (string, error) {
+	return fetchSyspath(vTouch.deviceFile)
+}
+
+// Emit will send a single raw event to the device, immediately followed by a SYN_REPORT.
+func (vTouch vPrecisionTouchPad) Emit(evType uint16, code uint16, value int32) error {
+	return emitEvent(vTouch.deviceFile, evType, code, value)
+}
+
+// NewFrame will create an empty frame that may be used to send multiple events at once.
+func (vTouch vPrecisionTouchPad) NewFrame() *Frame {
+	return newFrame(vTouch.deviceFile)
+}
+
+// SetEventHandler registers a handler that is invoked for every event sent to the device.
+func (vTouch vPrecisionTouchPad) SetEventHandler(handler EventHandler) {
+	vTouch.reader.setHandler(handler)
+}
+
+// ReleaseAll will lift all contacts and release all buttons that are currently held down.
+func (vTouch vPrecisionTouchPad) ReleaseAll() error {
+	err := vTouch.contacts.releaseAll()
+	if err != nil {
+		return err
+	}
+	return releaseAll(vTouch.deviceFile)
+}
+
+func (vTouch vPrecisionTouchPad) Close() error {
+	_ = vTouch.contacts.releaseAll()
+	return closeDevice(vTouch.deviceFile)
+}
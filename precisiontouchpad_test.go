@@ -0,0 +1,234 @@
+package uinput
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPrecisionTouchPadRegistersPropertiesAndResolution(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+	dev, err := CreatePrecisionTouchPad(fake.Path(), []byte("Test Precision TouchPad"), PrecisionTouchPadConfig{Width: 1200, Height: 800, Resolution: 12, ButtonPad: true})
+	if err != nil {
+		t.Fatalf("Failed to create the virtual precision touch pad. Last error was: %s\n", err)
+	}
+	fd := fake.Device("Test Precision TouchPad")
+	defer dev.Close()
+
+	if !fd.HasProperty(PropPointer) || !fd.HasProperty(PropButtonpad) {
+		t.Fatalf("Expected the touch pad to declare INPUT_PROP_POINTER and INPUT_PROP_BUTTONPAD")
+	}
+	fd.ExpectCodes(t, EvKey, ButtonLeft, ButtonToolFinger, ButtonToolQuintTap, ButtonTouch, ButtonToolDoubleTap,
+		ButtonToolTripleTap, ButtonToolQuadTap)
+
+	for _, code := range []uint16{AbsX, AbsMtPositionX} {
+		axis, ok := fd.Axis(code)
+		if !ok || axis.Max != 1200 || axis.Resolution != 12 {
+			t.Fatalf("Expected axis %d to range from 0 to 1200 with a resolution of 12, but got %+v", code, axis)
+		}
+	}
+	axis, ok := fd.Axis(AbsMtPressure)
+	if !ok || axis.Max != 255 {
+		t.Fatalf("Expected a pressure axis ranging from 0 to 255, but got %+v", axis)
+	}
+	axis, ok = fd.Axis(AbsMtSlot)
+	if !ok || axis.Max != 4 {
+		t.Fatalf("Expected 5 slots, but got %+v", axis)
+	}
+}
+
+func TestPrecisionTouchPadSetTouchesSendsSingleFrames(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+	dev, err := CreatePrecisionTouchPad(fake.Path(), []byte("Test Precision TouchPad"), PrecisionTouchPadConfig{Width: 1200, Height: 800, Resolution: 12})
+	if err != nil {
+		t.Fatalf("Failed to create the virtual precision touch pad. Last error was: %s\n", err)
+	}
+	fd := fake.Device("Test Precision TouchPad")
+	defer dev.Close()
+
+	err = dev.SetTouches([]Touch{{Slot: 0, X: 100, Y: 200}, {Slot: 1, X: 300, Y: 200, Pressure: 80}})
+	if err != nil {
+		t.Fatalf("Failed to set touches. Last error was: %s\n", err)
+	}
+	fd.ExpectEvents(t,
+		Event{Type: EvAbs, Code: AbsMtTrackingID, Value: 0},
+		Event{Type: EvAbs, Code: AbsMtPositionX, Value: 100},
+		Event{Type: EvAbs, Code: AbsMtPositionY, Value: 200},
+		Event{Type: EvAbs, Code: AbsMtPressure, Value: 127},
+		Event{Type: EvAbs, Code: AbsMtSlot, Value: 1},
+		Event{Type: EvAbs, Code: AbsMtTrackingID, Value: 1},
+		Event{Type: EvAbs, Code: AbsMtPositionX, Value: 300},
+		Event{Type: EvAbs, Code: AbsMtPositionY, Value: 200},
+		Event{Type: EvAbs, Code: AbsMtPressure, Value: 80},
+		Event{Type: EvKey, Code: ButtonTouch, Value: 1},
+		Event{Type: EvKey, Code: ButtonToolDoubleTap, Value: 1},
+		Event{Type: EvAbs, Code: AbsX, Value: 100},
+		Event{Type: EvAbs, Code: AbsY, Value: 200},
+		Event{Type: EvAbs, Code: AbsPressure, Value: 127},
+		Event{Type: EvSyn, Code: SynReport})
+
+	// the first finger is lifted, so the second one takes over the emulated pointer
+	err = dev.SetTouches([]Touch{{Slot: 1, X: 310, Y: 200, Pressure: 80}})
+	if err != nil {
+		t.Fatalf("Failed to set touches. Last error was: %s\n", err)
+	}
+	fd.ExpectEvents(t,
+		Event{Type: EvAbs, Code: AbsMtPositionX, Value: 310},
+		Event{Type: EvAbs, Code: AbsMtSlot, Value: 0},
+		Event{Type: EvAbs, Code: AbsMtTrackingID, Value: -1},
+		Event{Type: EvKey, Code: ButtonToolDoubleTap, Value: 0},
+		Event{Type: EvKey, Code: ButtonToolFinger, Value: 1},
+		Event{Type: EvAbs, Code: AbsX, Value: 310},
+		Event{Type: EvAbs, Code: AbsPressure, Value: 80},
+		Event{Type: EvSyn, Code: SynReport})
+
+	err = dev.SetTouches(nil)
+	if err != nil {
+		t.Fatalf("Failed to lift all touches. Last error was: %s\n", err)
+	}
+	fd.ExpectEvents(t,
+		Event{Type: EvAbs, Code: AbsMtSlot, Value: 1},
+		Event{Type: EvAbs, Code: AbsMtTrackingID, Value: -1},
+		Event{Type: EvKey, Code: ButtonTouch, Value: 0},
+		Event{Type: EvKey, Code: ButtonToolFinger, Value: 0},
+		Event{Type: EvAbs, Code: AbsPressure, Value: 0},
+		Event{Type: EvSyn, Code: SynReport})
+}
+
+func TestPrecisionTouchPadSetTouchesFailsForInvalidSlot(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+	dev, err := CreatePrecisionTouchPad(fake.Path(), []byte("Test Precision TouchPad"), PrecisionTouchPadConfig{Width: 1200, Height: 800, Resolution: 12, Slots: 2})
+	if err != nil {
+		t.Fatalf("Failed to create the virtual precision touch pad. Last error was: %s\n", err)
+	}
+	fd := fake.Device("Test Precision TouchPad")
+	defer dev.Close()
+
+	err = dev.SetTouches([]Touch{{Slot: 0, X: 1, Y: 1}, {Slot: 2, X: 1, Y: 1}})
+	if err == nil {
+		t.Fatalf("Expected setting a touch in an unknown slot to fail")
+	}
+	fd.ExpectNoEvents(t)
+}
+
+func TestPrecisionTouchPadContactPressure(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+	dev, err := CreatePrecisionTouchPad(fake.Path(), []byte("Test Precision TouchPad"), PrecisionTouchPadConfig{Width: 1200, Height: 800, Resolution: 12, MaxPressure: 100})
+	if err != nil {
+		t.Fatalf("Failed to create the virtual precision touch pad. Last error was: %s\n", err)
+	}
+	fd := fake.Device("Test Precision TouchPad")
+	defer dev.Close()
+
+	contact := dev.GetContacts()[0]
+	err = contact.DownWithPressure(10, 20, 30)
+	if err != nil {
+		t.Fatalf("Failed to put contact down. Last error was: %s\n", err)
+	}
+	fd.ClearEvents()
+
+	err = contact.MoveWithPressure(10, 20, 60)
+	if err != nil {
+		t.Fatalf("Failed to change pressure. Last error was: %s\n", err)
+	}
+	fd.ExpectEvents(t,
+		Event{Type: EvAbs, Code: AbsMtPressure, Value: 60},
+		Event{Type: EvAbs, Code: AbsPressure, Value: 60},
+		Event{Type: EvSyn, Code: SynReport})
+	if contact.Pressure() != 60 {
+		t.Fatalf("Expected: %d\nActual: %d", 60, contact.Pressure())
+	}
+}
+
+func TestPrecisionTouchPadButtons(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+	dev, err := CreatePrecisionTouchPad(fake.Path(), []byte("Test Precision TouchPad"), PrecisionTouchPadConfig{Width: 1200, Height: 800, Resolution: 12, ButtonPad: true})
+	if err != nil {
+		t.Fatalf("Failed to create the virtual precision touch pad. Last error was: %s\n", err)
+	}
+	fd := fake.Device("Test Precision TouchPad")
+	defer dev.Close()
+
+	err = dev.ButtonClick(ButtonLeft)
+	if err != nil {
+		t.Fatalf("Failed to click the left button. Last error was: %s\n", err)
+	}
+	fd.ExpectEvents(t,
+		Event{Type: EvKey, Code: ButtonLeft, Value: 1},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvKey, Code: ButtonLeft, Value: 0},
+		Event{Type: EvSyn, Code: SynReport})
+
+	err = dev.ButtonPress(ButtonRight)
+	if err == nil {
+		t.Fatalf("Expected pressing the right button of a clickpad to fail")
+	}
+}
+
+func TestPrecisionTouchPadSwipe(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+	dev, err := CreatePrecisionTouchPad(fake.Path(), []byte("Test Precision TouchPad"), PrecisionTouchPadConfig{Width: 1200, Height: 800, Resolution: 12})
+	if err != nil {
+		t.Fatalf("Failed to create the virtual precision touch pad. Last error was: %s\n", err)
+	}
+	fd := fake.Device("Test Precision TouchPad")
+	defer dev.Close()
+
+	err = dev.Swipe(3, 600, 400, 600, 100, GestureOptions{Duration: 10 * time.Millisecond, StepRate: 100})
+	if err != nil {
+		t.Fatalf("Failed to swipe. Last error was: %s\n", err)
+	}
+	frames := splitFrames(fd.Events())
+	if len(frames) != 3 {
+		t.Fatalf("Expected 3 frames (down, step, up), but got %d: %v", len(frames), frames)
+	}
+	for _, c := range dev.GetContacts() {
+		if c.IsDown() {
+			t.Fatalf("Expected all contacts to be lifted after the swipe")
+		}
+	}
+}
+
+func TestPrecisionTouchPadCreationFailsWithoutResolution(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	_, err := CreatePrecisionTouchPad(fake.Path(), []byte("Test Precision TouchPad"), PrecisionTouchPadConfig{Width: 1200, Height: 800})
+	if err == nil {
+		t.Fatalf("Expected creation to fail without a resolution")
+	}
+	if len(fake.Devices()) != 0 {
+		t.Fatalf("Expected no device to be created")
+	}
+}
+
+func TestPrecisionTouchPadCreationFailsIfNameIsTooLong(t *testing.T) {
+	name := "adsfdsferqewoirueworiuejdsfjdfa;ljoewrjeworiewuoruew;rj;kdlfjoeai;jfewoaifjef;das"
+	_, err := CreatePrecisionTouchPad("/dev/uinput", []byte(name), PrecisionTouchPadConfig{Width: 1200, Height: 800, Resolution: 12})
+	if err == nil {
+		t.Fatalf("Expected touch pad creation to fail due to a name that is too long")
+	}
+}
+
+func TestPrecisionTouchPadSetTouchesFailsOnClosedDevice(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+	dev, err := CreatePrecisionTouchPad(fake.Path(), []byte("Test Precision TouchPad"), PrecisionTouchPadConfig{Width: 1200, Height: 800, Resolution: 12})
+	if err != nil {
+		t.Fatalf("Failed to create the virtual precision touch pad. Last error was: %s\n", err)
+	}
+	err = dev.Close()
+	if err != nil {
+		t.Fatalf("Failed to close device. Last error was: %s\n", err)
+	}
+
+	err = dev.SetTouches([]Touch{{Slot: 0, X: 1, Y: 1}})
+	if err == nil {
+		t.Fatalf("Expected SetTouches to fail on a closed device")
+	}
+}
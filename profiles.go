@@ -0,0 +1,173 @@
+package uinput
+
+import "fmt"
+
+// A GamepadProfile describes a specific controller model the way its kernel driver exposes it: name, identity,
+// buttons, axes (including their ranges) and force feedback effects. Gamepads created from a profile are therefore
+// indistinguishable from the real controller for applications like SDL or Steam, which rely on these details to
+// map the controls.
+//
+// The version of the identity depends on the firmware of the controller; the profiles use common values, which may
+// be changed using the WithID option.
+type GamepadProfile struct {
+	Name          string
+	ID            InputID
+	Keys          []uint16
+	Axes          []AxisConfig
+	ForceFeedback []uint16
+}
+
+// sticks of controllers handled by xpad (ranging from -32768 to 32767, fuzz 16, flat 128)
+func xpadStick(code uint16) AxisConfig {
+	return AxisConfig{Code: code, Min: -32768, Max: 32767, Fuzz: 16, Flat: 128}
+}
+
+// sticks of controllers handled by hid-nintendo (ranging from -32767 to 32767, fuzz 250, flat 500)
+func nintendoStick(code uint16) AxisConfig {
+	return AxisConfig{Code: code, Min: -32767, Max: 32767, Fuzz: 250, Flat: 500}
+}
+
+func unsignedAxis(code uint16, max int32) AxisConfig {
+	return AxisConfig{Code: code, Min: 0, Max: max}
+}
+
+func hatAxis(code uint16) AxisConfig {
+	return AxisConfig{Code: code, Min: -1, Max: 1}
+}
+
+// buttons of controllers handled by xpad (with the d-pad mapped onto the hat)
+var xpadKeys = []uint16{
+	ButtonSouth, ButtonEast, ButtonNorth, ButtonWest,
+	ButtonStart, ButtonSelect, ButtonThumbLeft, ButtonThumbRight,
+	ButtonBumperLeft, ButtonBumperRight, ButtonMode,
+}
+
+// buttons of controllers handled by hid-playstation
+var playstationKeys = []uint16{
+	ButtonWest, ButtonNorth, ButtonEast, ButtonSouth,
+	ButtonBumperLeft, ButtonBumperRight, ButtonTriggerLeft, ButtonTriggerRight,
+	ButtonSelect, ButtonStart, ButtonThumbLeft, ButtonThumbRight, ButtonMode,
+}
+
+// ProfileXbox360 is the wired Xbox 360 controller (xpad).
+var ProfileXbox360 = GamepadProfile{
+	Name: "Microsoft X-Box 360 pad",
+	ID:   InputID{Bustype: BusUsb, Vendor: 0x045e, Product: 0x028e, Version: 0x0114},
+	Keys: xpadKeys,
+	Axes: []AxisConfig{
+		xpadStick(AbsX), xpadStick(AbsY), xpadStick(AbsRX), xpadStick(AbsRY),
+		unsignedAxis(AbsZ, 255), unsignedAxis(AbsRZ, 255),
+		hatAxis(AbsHat0X), hatAxis(AbsHat0Y),
+	},
+	ForceFeedback: memlessFFEffects,
+}
+
+// ProfileXboxOne is the Xbox One S controller, connected via USB (xpad).
+var ProfileXboxOne = GamepadProfile{
+	Name: "Microsoft X-Box One S pad",
+	ID:   InputID{Bustype: BusUsb, Vendor: 0x045e, Product: 0x02ea, Version: 0x0301},
+	Keys: xpadKeys,
+	Axes: []AxisConfig{
+		xpadStick(AbsX), xpadStick(AbsY), xpadStick(AbsRX), xpadStick(AbsRY),
+		unsignedAxis(AbsZ, 1023), unsignedAxis(AbsRZ, 1023),
+		hatAxis(AbsHat0X), hatAxis(AbsHat0Y),
+	},
+	ForceFeedback: memlessFFEffects,
+}
+
+// ProfileXboxSeries is the Xbox Series X|S controller, connected via USB (xpad). Its share button is reported as
+// KeyRecord.
+var ProfileXboxSeries = GamepadProfile{
+	Name: "Microsoft Xbox Series S|X Controller",
+	ID:   InputID{Bustype: BusUsb, Vendor: 0x045e, Product: 0x0b12, Version: 0x0509},
+	Keys: append(append([]uint16(nil), xpadKeys...), KeyRecord),
+	Axes: []AxisConfig{
+		xpadStick(AbsX), xpadStick(AbsY), xpadStick(AbsRX), xpadStick(AbsRY),
+		unsignedAxis(AbsZ, 1023), unsignedAxis(AbsRZ, 1023),
+		hatAxis(AbsHat0X), hatAxis(AbsHat0Y),
+	},
+	ForceFeedback: memlessFFEffects,
+}
+
+// ProfileDualShock4 is the DualShock 4 (second revision), connected via USB (hid-playstation). The touch pad and the
+// motion sensors of the real controller are separate devices.
+var ProfileDualShock4 = GamepadProfile{
+	Name: "Sony Interactive Entertainment Wireless Controller",
+	ID:   InputID{Bustype: BusUsb, Vendor: 0x054c, Product: 0x09cc, Version: 0x8111},
+	Keys: playstationKeys,
+	Axes: []AxisConfig{
+		unsignedAxis(AbsX, 255), unsignedAxis(AbsY, 255), unsignedAxis(AbsZ, 255),
+		unsignedAxis(AbsRX, 255), unsignedAxis(AbsRY, 255), unsignedAxis(AbsRZ, 255),
+		hatAxis(AbsHat0X), hatAxis(AbsHat0Y),
+	},
+	ForceFeedback: memlessFFEffects,
+}
+
+// ProfileDualSense is the DualSense, connected via USB (hid-playstation). The touch pad and the motion sensors of
+// the real controller are separate devices.
+var ProfileDualSense = GamepadProfile{
+	Name: "Sony Interactive Entertainment DualSense Wireless Controller",
+	ID:   InputID{Bustype: BusUsb, Vendor: 0x054c, Product: 0x0ce6, Version: 0x8111},
+	Keys: playstationKeys,
+	Axes: []AxisConfig{
+		unsignedAxis(AbsX, 255), unsignedAxis(AbsY, 255), unsignedAxis(AbsZ, 255),
+		unsignedAxis(AbsRX, 255), unsignedAxis(AbsRY, 255), unsignedAxis(AbsRZ, 255),
+		hatAxis(AbsHat0X), hatAxis(AbsHat0Y),
+	},
+	ForceFeedback: memlessFFEffects,
+}
+
+// ProfileSwitchPro is the Nintendo Switch Pro Controller, connected via USB (hid-nintendo). Its triggers are digital
+// and the capture button is reported as ButtonZ. The motion sensors of the real controller are a separate device.
+var ProfileSwitchPro = GamepadProfile{
+	Name: "Nintendo Switch Pro Controller",
+	ID:   InputID{Bustype: BusUsb, Vendor: 0x057e, Product: 0x2009, Version: 0x8111},
+	Keys: []uint16{
+		ButtonEast, ButtonSouth, ButtonNorth, ButtonWest,
+		ButtonBumperLeft, ButtonBumperRight, ButtonTriggerLeft, ButtonTriggerRight,
+		ButtonSelect, ButtonStart, ButtonThumbLeft, ButtonThumbRight, ButtonMode, ButtonZ,
+	},
+	Axes: []AxisConfig{
+		nintendoStick(AbsX), nintendoStick(AbsY), nintendoStick(AbsRX), nintendoStick(AbsRY),
+		hatAxis(AbsHat0X), hatAxis(AbsHat0Y),
+	},
+	ForceFeedback: memlessFFEffects,
+}
+
+// CreateGamepadFromProfile will create a new gamepad that reproduces the controller described by the profile. Force
+// feedback events are passed on to the given handler, which may be nil if they are of no interest.
+func CreateGamepadFromProfile(path string, profile GamepadProfile, handler FFHandler, options ...DeviceOption) (Gamepad, error) {
+	err := validateDevicePath(path)
+	if err != nil {
+		return nil, err
+	}
+	name := []byte(profile.Name)
+	err = validateUinputName(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(profile.Keys) == 0 || len(profile.Axes) == 0 {
+		return nil, fmt.Errorf("gamepad profile %q must declare keys and axes", profile.Name)
+	}
+
+	axes := append([]AxisConfig(nil), profile.Axes...)
+	opts := newGamepadOptions(options)
+	axes, err = addHatAxes(axes, opts.hats)
+	if err != nil {
+		return nil, err
+	}
+	fd, err := createVGamepadDevice(path, name, profile.ID, append([]uint16(nil), profile.Keys...), axes,
+		profile.ForceFeedback, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var reader *eventReader
+	if len(profile.ForceFeedback) > 0 {
+		reader = startEventReader(fd, newForceFeedback(fd, handler))
+	} else {
+		reader = startEventReader(fd, nil)
+	}
+
+	return newVGamepad(name, fd, profile.ID, profile.Keys, axes, reader, opts), nil
+}
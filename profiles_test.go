@@ -0,0 +1,109 @@
+package uinput
+
+import "testing"
+
+func TestXbox360ProfileMatchesXpad(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	gamepad, err := CreateGamepadFromProfile(fake.Path(), ProfileXbox360, nil)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+	defer gamepad.Close()
+
+	dev := fake.Device("Microsoft X-Box 360 pad")
+	expected := InputID{Bustype: BusUsb, Vendor: 0x045e, Product: 0x028e, Version: 0x0114}
+	if dev.ID() != expected {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, dev.ID())
+	}
+	dev.ExpectCodes(t, EvKey, ButtonSouth, ButtonEast, ButtonNorth, ButtonWest, ButtonBumperLeft, ButtonBumperRight,
+		ButtonSelect, ButtonStart, ButtonMode, ButtonThumbLeft, ButtonThumbRight)
+	dev.ExpectCodes(t, EvAbs, AbsX, AbsY, AbsZ, AbsRX, AbsRY, AbsRZ, AbsHat0X, AbsHat0Y)
+	dev.ExpectCodes(t, EvFf, uint16(FFRumble), uint16(FFPeriodic), uint16(FFSquare), uint16(FFTriangle),
+		uint16(FFSine), ffGain)
+
+	axis, _ := dev.Axis(AbsX)
+	if axis.Min != -32768 || axis.Max != 32767 || axis.Fuzz != 16 || axis.Flat != 128 {
+		t.Fatalf("Expected the x-axis to match xpad, but got %+v", axis)
+	}
+	axis, _ = dev.Axis(AbsZ)
+	if axis.Min != 0 || axis.Max != 255 {
+		t.Fatalf("Expected the left trigger to range from 0 to 255, but got %+v", axis)
+	}
+	if dev.EffectsMax() == 0 {
+		t.Fatalf("Expected the gamepad to support force feedback effects")
+	}
+}
+
+func TestSwitchProProfileHasNoAnalogTriggers(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	gamepad, err := CreateGamepadFromProfile(fake.Path(), ProfileSwitchPro, nil)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+	defer gamepad.Close()
+
+	dev := fake.Device("Nintendo Switch Pro Controller")
+	if !dev.HasCode(EvKey, ButtonZ) || !dev.HasCode(EvKey, ButtonTriggerLeft) {
+		t.Fatalf("Expected the capture button and digital triggers to be registered")
+	}
+	if dev.HasCode(EvAbs, AbsZ) || dev.HasCode(EvAbs, AbsRZ) {
+		t.Fatalf("Expected no analog triggers to be registered")
+	}
+}
+
+func TestProfileSticksUseProfileRanges(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	gamepad, err := CreateGamepadFromProfile(fake.Path(), ProfileDualShock4, nil)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+	defer gamepad.Close()
+
+	err = gamepad.LeftStickMoveX(1)
+	if err != nil {
+		t.Fatalf("Failed to move the left stick. Last error was: %s\n", err)
+	}
+	err = gamepad.LeftStickMoveY(0)
+	if err != nil {
+		t.Fatalf("Failed to move the left stick. Last error was: %s\n", err)
+	}
+	fake.Device("Sony Interactive Entertainment Wireless Controller").ExpectEvents(t,
+		Event{Type: EvAbs, Code: AbsX, Value: 255},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvAbs, Code: AbsY, Value: 127},
+		Event{Type: EvSyn, Code: SynReport})
+}
+
+func TestProfileIdentityMayBeOverridden(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	id := ProfileDualSense.ID
+	id.Bustype = BusBluetooth
+	gamepad, err := CreateGamepadFromProfile(fake.Path(), ProfileDualSense, nil, WithID(id))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+	defer gamepad.Close()
+
+	actual := fake.Device(ProfileDualSense.Name).ID()
+	if actual != id {
+		t.Fatalf("Expected: %+v\nActual: %+v", id, actual)
+	}
+}
+
+func TestProfileCreationFailsWithoutControls(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	_, err := CreateGamepadFromProfile(fake.Path(), GamepadProfile{Name: "Empty"}, nil)
+	if err == nil {
+		t.Fatalf("Expected gamepad creation to fail for a profile without keys and axes")
+	}
+}
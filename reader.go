@@ -0,0 +1,107 @@
+package uinput
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sync"
+)
+
+// Event is an event that was sent to a device by the system, e.g. a led change issued by the compositor, a bell or
+// a force feedback request. See the Ev* constants and the related codes in keycodes.go.
+type Event struct {
+	Type  uint16
+	Code  uint16
+	Value int32
+}
+
+// EventHandler receives the events sent to a device. It is invoked from a separate goroutine.
+type EventHandler func(event Event)
+
+// An EventReceiver gives access to the events that are sent to a device by the system.
+type EventReceiver interface {
+	// SetEventHandler registers a handler that is invoked for every event sent to the device.
+	// Passing nil will remove a previously registered handler.
+	SetEventHandler(handler EventHandler)
+}
+
+// LEDState holds the state of the leds of a device (see the Led* constants in keycodes.go).
+type LEDState uint32
+
+// IsOn will return true if the given led is switched on.
+func (s LEDState) IsOn(led uint16) bool {
+	return led < 32 && s&(1<<led) != 0
+}
+
+type eventReader struct {
+	deviceFile uinputFile
+	ff         *forceFeedback
+	mutex      sync.Mutex
+	handler    EventHandler
+	leds       LEDState
+}
+
+// startEventReader will start reading the events sent to the device until the device file is closed. Force feedback
+// requests are passed on to ff, if given.
+func startEventReader(deviceFile uinputFile, ff *forceFeedback) *eventReader {
+	reader := &eventReader{deviceFile: deviceFile, ff: ff}
+	go reader.run()
+	return reader
+}
+
+func (r *eventReader) run() {
+	size := binary.Size(inputEvent{})
+	buf := make([]byte, size*16)
+	for {
+		n, err := r.deviceFile.Read(buf)
+		if err != nil {
+			return
+		}
+		events := bytes.NewReader(buf[:n-n%size])
+		for events.Len() > 0 {
+			var ev inputEvent
+			if binary.Read(events, binary.LittleEndian, &ev) != nil {
+				break
+			}
+			r.handleEvent(ev)
+		}
+	}
+}
+
+func (r *eventReader) handleEvent(ev inputEvent) {
+	if r.ff != nil && (ev.Type == evUinput || ev.Type == evFf) {
+		r.ff.handleEvent(ev)
+	}
+
+	r.mutex.Lock()
+	if ev.Type == evLed && ev.Code < 32 {
+		if ev.Value != 0 {
+			r.leds |= 1 << ev.Code
+		} else {
+			r.leds &^= 1 << ev.Code
+		}
+	}
+	handler := r.handler
+	r.mutex.Unlock()
+
+	if handler != nil {
+		handler(Event{Type: ev.Type, Code: ev.Code, Value: ev.Value})
+	}
+}
+
+func (r *eventReader) setHandler(handler EventHandler) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.handler = handler
+}
+
+func (r *eventReader) ledState() LEDState {
+	if r == nil {
+		return 0
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.leds
+}
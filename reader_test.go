@@ -0,0 +1,72 @@
+package uinput
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestKeyboardLEDsAreOffInitially(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	vk, err := CreateKeyboard(fake.Path(), []byte("Test LED Keyboard"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	defer vk.Close()
+
+	if vk.LEDs().IsOn(LedCapsLock) {
+		t.Fatalf("Expected caps lock to be off")
+	}
+}
+
+func TestEventReaderTracksLEDsAndNotifiesHandler(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create pipe: %v", err)
+	}
+	defer w.Close()
+
+	received := make(chan Event, 4)
+	reader := startEventReader(osFile{r}, nil)
+	reader.setHandler(func(event Event) {
+		received <- event
+	})
+
+	err = writeEvents(osFile{w}, []inputEvent{
+		{Type: evLed, Code: LedCapsLock, Value: 1},
+		{Type: evLed, Code: LedNumLock, Value: 1},
+		{Type: evLed, Code: LedNumLock, Value: 0},
+	})
+	if err != nil {
+		t.Fatalf("Failed to write events: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		select {
+		case event := <-received:
+			if event.Type != evLed {
+				t.Fatalf("Expected led event, but got %+v", event)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for event %d", i)
+		}
+	}
+
+	leds := reader.ledState()
+	if !leds.IsOn(LedCapsLock) || leds.IsOn(LedNumLock) || leds.IsOn(LedScrollLock) {
+		t.Fatalf("Unexpected led state: %b", leds)
+	}
+
+	// closing the file must stop the reader
+	_ = r.Close()
+}
+
+func TestLEDStateOfMissingReader(t *testing.T) {
+	var reader *eventReader
+	reader.setHandler(func(event Event) {})
+	if reader.ledState() != 0 {
+		t.Fatalf("Expected all leds to be off")
+	}
+}
@@ -0,0 +1,141 @@
+package uinput
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"syscall"
+)
+
+// A Releaser is a device that is able to release all of its keys, buttons and touch contacts that are currently held
+// down. All devices of this package implement this interface.
+type Releaser interface {
+	// ReleaseAll will release all keys, buttons and touch contacts that are currently held down, followed by a single
+	// SYN_REPORT. Nothing is sent if nothing is held down.
+	ReleaseAll() error
+}
+
+// ReleaseOnSignal will release everything that is held down on the given devices as soon as the process receives
+// SIGINT or SIGTERM. Afterwards, the signal handling is reset and the signal is raised again, so that the process
+// terminates as usual. The returned function stops watching for signals. Note that this does not cover panics, so
+// make sure to call Close (which releases everything as well) using defer.
+func ReleaseOnSignal(devices ...Releaser) (stop func()) {
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case sig := <-signals:
+			for _, device := range devices {
+				_ = device.ReleaseAll()
+			}
+			signal.Stop(signals)
+			_ = syscall.Kill(os.Getpid(), sig.(syscall.Signal))
+		case <-done:
+			signal.Stop(signals)
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+// trackingFile keeps track of the keys, buttons and touch contacts that are currently held down, based on the events
+// written to the device file. This way, everything can be released before the device is closed.
+type trackingFile struct {
+	uinputFile
+	mutex    sync.Mutex
+	keys     map[uint16]bool
+	contacts map[int32]bool
+	slot     int32
+}
+
+func newTrackingFile(deviceFile uinputFile) *trackingFile {
+	return &trackingFile{uinputFile: deviceFile, keys: make(map[uint16]bool), contacts: make(map[int32]bool)}
+}
+
+func (f *trackingFile) Write(p []byte) (int, error) {
+	n, err := f.uinputFile.Write(p)
+	f.track(p[:n])
+	return n, err
+}
+
+func (f *trackingFile) track(p []byte) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	size := binary.Size(inputEvent{})
+	reader := bytes.NewReader(p[:len(p)-len(p)%size])
+	for reader.Len() > 0 {
+		var ev inputEvent
+		if binary.Read(reader, binary.LittleEndian, &ev) != nil {
+			return
+		}
+		switch {
+		case ev.Type == evKey && ev.Value != 0:
+			f.keys[ev.Code] = true
+		case ev.Type == evKey:
+			delete(f.keys, ev.Code)
+		case ev.Type == evAbs && ev.Code == absMtSlot:
+			f.slot = ev.Value
+		case ev.Type == evAbs && ev.Code == absMtTrackingId && ev.Value >= 0:
+			f.contacts[f.slot] = true
+		case ev.Type == evAbs && ev.Code == absMtTrackingId:
+			delete(f.contacts, f.slot)
+		}
+	}
+}
+
+// releaseEvents will return the events required to release everything that is currently held down (in ascending
+// order of key codes and slots), without a trailing SYN_REPORT.
+func (f *trackingFile) releaseEvents() []inputEvent {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var events []inputEvent
+	var slots []int
+	for slot := range f.contacts {
+		slots = append(slots, int(slot))
+	}
+	sort.Ints(slots)
+	for _, slot := range slots {
+		events = append(events,
+			inputEvent{Type: evAbs, Code: absMtSlot, Value: int32(slot)},
+			inputEvent{Type: evAbs, Code: absMtTrackingId, Value: -1})
+	}
+
+	var keys []int
+	for key := range f.keys {
+		keys = append(keys, int(key))
+	}
+	sort.Ints(keys)
+	for _, key := range keys {
+		events = append(events, inputEvent{Type: evKey, Code: uint16(key), Value: btnStateReleased})
+	}
+	return events
+}
+
+// releaseAll will release everything that is held down on the device. Devices that do not track their state (like
+// those that could not be created) are ignored.
+func releaseAll(deviceFile uinputFile) error {
+	f, ok := deviceFile.(*trackingFile)
+	if !ok {
+		return nil
+	}
+	events := f.releaseEvents()
+	if len(events) == 0 {
+		return nil
+	}
+	err := writeEvents(f, append(events, inputEvent{Type: evSyn, Code: synReport}))
+	if err != nil {
+		return fmt.Errorf("failed to release keys, buttons and contacts: %v", err)
+	}
+	return nil
+}
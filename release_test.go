@@ -0,0 +1,110 @@
+package uinput
+
+import "testing"
+
+func TestCloseReleasesHeldKeys(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	vk, err := CreateKeyboard(fake.Path(), []byte("Test Keyboard"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	dev := fake.Device("Test Keyboard")
+
+	err = vk.KeyDown(KeyLeftshift)
+	if err != nil {
+		t.Fatalf("Failed to send key down event. Last error was: %s\n", err)
+	}
+	err = vk.KeyDown(KeyA)
+	if err != nil {
+		t.Fatalf("Failed to send key down event. Last error was: %s\n", err)
+	}
+	dev.ClearEvents()
+
+	err = vk.Close()
+	if err != nil {
+		t.Fatalf("Failed to close device. Last error was: %s\n", err)
+	}
+	dev.ExpectEvents(t,
+		Event{Type: EvKey, Code: KeyA, Value: 0},
+		Event{Type: EvKey, Code: KeyLeftshift, Value: 0},
+		Event{Type: EvSyn, Code: SynReport})
+}
+
+func TestReleaseAllReleasesMouseButtons(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	mouse, err := CreateMouse(fake.Path(), []byte("Test Mouse"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual mouse. Last error was: %s\n", err)
+	}
+	defer mouse.Close()
+	dev := fake.Device("Test Mouse")
+
+	err = mouse.LeftPress()
+	if err != nil {
+		t.Fatalf("Failed to press the left button. Last error was: %s\n", err)
+	}
+	dev.ClearEvents()
+
+	err = mouse.ReleaseAll()
+	if err != nil {
+		t.Fatalf("Failed to release all buttons. Last error was: %s\n", err)
+	}
+	dev.ExpectEvents(t,
+		Event{Type: EvKey, Code: evMouseBtnLeft, Value: 0},
+		Event{Type: EvSyn, Code: SynReport})
+
+	// nothing is held down anymore, so nothing must be sent
+	err = mouse.ReleaseAll()
+	if err != nil {
+		t.Fatalf("Failed to release all buttons. Last error was: %s\n", err)
+	}
+	dev.ExpectNoEvents(t)
+}
+
+func TestReleaseAllLiftsTouchContacts(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	multitouch, err := CreateMultiTouch(fake.Path(), []byte("Test MultiTouch"), 0, 1024, 0, 768, 3)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual multitouch device. Last error was: %s\n", err)
+	}
+	defer multitouch.Close()
+	dev := fake.Device("Test MultiTouch")
+
+	contacts := multitouch.GetContacts()
+	for _, contact := range []int{2, 0} {
+		err = contacts[contact].TouchDownAt(100, 100)
+		if err != nil {
+			t.Fatalf("Failed to touch down. Last error was: %s\n", err)
+		}
+	}
+	dev.ClearEvents()
+
+	err = multitouch.ReleaseAll()
+	if err != nil {
+		t.Fatalf("Failed to release all contacts. Last error was: %s\n", err)
+	}
+	dev.ExpectEvents(t,
+		Event{Type: EvAbs, Code: AbsMtTrackingID, Value: -1},
+		Event{Type: EvAbs, Code: AbsMtSlot, Value: 2},
+		Event{Type: EvAbs, Code: AbsMtTrackingID, Value: -1},
+		Event{Type: EvKey, Code: ButtonTouch, Value: 0},
+		Event{Type: EvKey, Code: ButtonToolDoubleTap, Value: 0},
+		Event{Type: EvSyn, Code: SynReport})
+	for _, contact := range contacts {
+		if contact.IsDown() {
+			t.Fatalf("Expected contact %d to be up", contact.Slot())
+		}
+	}
+}
+
+func TestReleaseOnSignalCanBeStopped(t *testing.T) {
+	stop := ReleaseOnSignal()
+	stop()
+	stop()
+}
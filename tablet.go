@@ -0,0 +1,337 @@
+package uinput
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// defaults and limits of a pen tablet
+const (
+	defaultTabletMaxPressure = 4095
+	defaultTabletMaxDistance = 63
+	tabletMinTilt            = -64
+	tabletMaxTilt            = 63
+	// resolution of the tilt axes in units per radian, so that one unit equals one degree
+	tabletTiltResolution = 57
+)
+
+// PenTool selects the end of the pen that is used.
+type PenTool int
+
+const (
+	// ToolPen is the tip of the pen.
+	ToolPen PenTool = iota
+	// ToolEraser is the eraser at the back of the pen.
+	ToolEraser
+)
+
+func (tool PenTool) code() (uint16, error) {
+	switch tool {
+	case ToolPen:
+		return ButtonToolPen, nil
+	case ToolEraser:
+		return ButtonToolRubber, nil
+	}
+	return 0, fmt.Errorf("pen tool %d is not supported", tool)
+}
+
+// TabletConfig describes the surface of a pen tablet. The zero value of optional fields selects sensible defaults.
+type TabletConfig struct {
+	// Width and Height of the surface in device units. The x-axis ranges from 0 to Width, the y-axis from 0 to Height.
+	Width  int32
+	Height int32
+	// Resolution of both axes in units per millimeter. It is required, since applications rely on the physical size
+	// of the surface (e.g. to keep the aspect ratio when mapping the tablet onto a screen).
+	Resolution int32
+	// MaxPressure of the pen. Defaults to 4095.
+	MaxPressure int32
+	// MaxDistance of the pen while hovering above the surface. Defaults to 63.
+	MaxDistance int32
+	// Direct declares a display tablet (the pen is used on the screen itself, like on an iPad), rather than a tablet
+	// that moves a pointer on a separate screen.
+	Direct bool
+}
+
+// PenState describes the pen of a tablet, as passed to Update. Values out of range are clamped.
+type PenState struct {
+	// Tool is the end of the pen that is used. Switching the tool moves the pen out of proximity first.
+	Tool PenTool
+	X    int32
+	Y    int32
+	// Pressure of the pen, 0 while hovering. The pen touches the surface as long as the pressure is positive.
+	Pressure int32
+	// Distance of the pen while hovering above the surface (0 to MaxDistance).
+	Distance int32
+	// TiltX and TiltY of the pen in degrees (-64 to 63), 0 being perpendicular to the surface.
+	TiltX int32
+	TiltY int32
+	// Stylus and Stylus2 are the lower and upper buttons on the side of the pen.
+	Stylus  bool
+	Stylus2 bool
+}
+
+// A Tablet is a pen tablet (like those of Wacom), reporting the position, pressure, hover distance and tilt of a pen.
+// The pen is brought into proximity by the first call to Update and remains in proximity until ProximityOut is
+// called, just like a real pen that is lifted away from the tablet.
+type Tablet interface {
+	// Update will send the changes of the pen state within a single frame, bringing the pen into proximity first if
+	// necessary.
+	Update(state PenState) error
+
+	// ProximityOut will lift the pen off the surface, release its buttons and move it out of proximity.
+	ProximityOut() error
+
+	// InProximity will return true if the pen is in proximity of the tablet.
+	InProximity() bool
+
+	// FetchSyspath will return the syspath to the device file.
+	FetchSyspath() (string, error)
+
+	EventEmitter
+
+	EventReceiver
+
+	Releaser
+
+	io.Closer
+}
+
+type vTablet struct {
+	name       []byte
+	deviceFile uinputFile
+	pen        *penState
+	reader     *eventReader
+}
+
+// CreateTablet will create a new pen tablet with the given surface.
+func CreateTablet(path string, name []byte, config TabletConfig, options ...DeviceOption) (Tablet, error) {
+	err := validateDevicePath(path)
+	if err != nil {
+		return nil, err
+	}
+	err = validateUinputName(name)
+	if err != nil {
+		return nil, err
+	}
+	config, err = config.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+
+	fd, err := createDevice(path, name, config.spec(), newDeviceOptions(options))
+	if err != nil {
+		return nil, err
+	}
+
+	return vTablet{
+		name:       name,
+		deviceFile: fd,
+		pen:        &penState{deviceFile: fd, config: config},
+		reader:     startEventReader(fd, nil)}, nil
+}
+
+func (config TabletConfig) withDefaults() (TabletConfig, error) {
+	if config.Width <= 0 || config.Height <= 0 {
+		return config, fmt.Errorf("width and height of the tablet must be positive, but are %d and %d",
+			config.Width, config.Height)
+	}
+	if config.Resolution <= 0 {
+		return config, fmt.Errorf("resolution of the tablet must be positive, but is %d", config.Resolution)
+	}
+	if config.MaxPressure < 0 || config.MaxDistance < 0 {
+		return config, fmt.Errorf("maximum pressure and distance must not be negative")
+	}
+	if config.MaxPressure == 0 {
+		config.MaxPressure = defaultTabletMaxPressure
+	}
+	if config.MaxDistance == 0 {
+		config.MaxDistance = defaultTabletMaxDistance
+	}
+	return config, nil
+}
+
+func (config TabletConfig) spec() DeviceSpec {
+	property := uint16(PropPointer)
+	if config.Direct {
+		property = PropDirect
+	}
+
+	return DeviceSpec{
+		ID:   InputID{Bustype: BusUsb, Vendor: 0x4711, Product: 0x081a, Version: 1},
+		Keys: []uint16{ButtonToolPen, ButtonToolRubber, ButtonTouch, ButtonStylus, ButtonStylus2},
+		AbsAxes: []AxisConfig{
+			{Code: AbsX, Min: 0, Max: config.Width, Resolution: config.Resolution},
+			{Code: AbsY, Min: 0, Max: config.Height, Resolution: config.Resolution},
+			{Code: AbsPressure, Min: 0, Max: config.MaxPressure},
+			{Code: AbsDistance, Min: 0, Max: config.MaxDistance},
+			{Code: AbsTiltX, Min: tabletMinTilt, Max: tabletMaxTilt, Resolution: tabletTiltResolution},
+			{Code: AbsTiltY, Min: tabletMinTilt, Max: tabletMaxTilt, Resolution: tabletTiltResolution},
+		},
+		Properties: []uint16{property},
+	}
+}
+
+func (vTab vTablet) Update(state PenState) error {
+	return vTab.pen.update(state)
+}
+
+func (vTab vTablet) ProximityOut() error {
+	return vTab.pen.proximityOut()
+}
+
+func (vTab vTablet) InProximity() bool {
+	vTab.pen.mutex.Lock()
+	defer vTab.pen.mutex.Unlock()
+	return vTab.pen.inProximity
+}
+
+func (vTab vTablet) FetchSyspath() (string, error) {
+	return fetchSyspath(vTab.deviceFile)
+}
+
+// Emit will send a single raw event to the device, immediately followed by a SYN_REPORT.
+func (vTab vTablet) Emit(evType uint16, code uint16, value int32) error {
+	return emitEvent(vTab.deviceFile, evType, code, value)
+}
+
+// NewFrame will create an empty frame that may be used to send multiple events at once.
+func (vTab vTablet) NewFrame() *Frame {
+	return newFrame(vTab.deviceFile)
+}
+
+// SetEventHandler registers a handler that is invoked for every event sent to the device.
+func (vTab vTablet) SetEventHandler(handler EventHandler) {
+	vTab.reader.setHandler(handler)
+}
+
+// ReleaseAll will move the pen out of proximity and release all buttons that are currently held down.
+func (vTab vTablet) ReleaseAll() error {
+	err := vTab.pen.proximityOut()
+	if err != nil {
+		return err
+	}
+	return releaseAll(vTab.deviceFile)
+}
+
+func (vTab vTablet) Close() error {
+	_ = vTab.pen.proximityOut()
+	return closeDevice(vTab.deviceFile)
+}
+
+// penState holds the last state sent for the pen of a tablet.
+type penState struct {
+	mutex       sync.Mutex
+	deviceFile  uinputFile
+	config      TabletConfig
+	inProximity bool
+	last        PenState
+}
+
+func (p *penState) update(state PenState) error {
+	tool, err := state.Tool.code()
+	if err != nil {
+		return fmt.Errorf("failed to update pen: %v", err)
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.inProximity && state.Tool != p.last.Tool {
+		err = p.sendProximityOut()
+		if err != nil {
+			return err
+		}
+	}
+
+	state.X = clamp(state.X, 0, p.config.Width)
+	state.Y = clamp(state.Y, 0, p.config.Height)
+	state.Pressure = clamp(state.Pressure, 0, p.config.MaxPressure)
+	state.Distance = clamp(state.Distance, 0, p.config.MaxDistance)
+	state.TiltX = clamp(state.TiltX, tabletMinTilt, tabletMaxTilt)
+	state.TiltY = clamp(state.TiltY, tabletMinTilt, tabletMaxTilt)
+
+	// all axes are sent when entering proximity, afterwards only those that changed
+	entering := !p.inProximity
+	frame := newFrame(p.deviceFile)
+	for _, axis := range []struct {
+		code     uint16
+		value    int32
+		previous int32
+	}{
+		{AbsX, state.X, p.last.X},
+		{AbsY, state.Y, p.last.Y},
+		{AbsPressure, state.Pressure, p.last.Pressure},
+		{AbsDistance, state.Distance, p.last.Distance},
+		{AbsTiltX, state.TiltX, p.last.TiltX},
+		{AbsTiltY, state.TiltY, p.last.TiltY},
+	} {
+		if entering || axis.value != axis.previous {
+			frame.Emit(evAbs, axis.code, axis.value)
+		}
+	}
+
+	if entering {
+		frame.Emit(evKey, tool, btnStatePressed)
+	}
+	for _, button := range []struct {
+		code     uint16
+		pressed  bool
+		previous bool
+	}{
+		{ButtonTouch, state.Pressure > 0, p.last.Pressure > 0},
+		{ButtonStylus, state.Stylus, p.last.Stylus},
+		{ButtonStylus2, state.Stylus2, p.last.Stylus2},
+	} {
+		if button.pressed != button.previous {
+			frame.Emit(evKey, button.code, boolToValue(button.pressed))
+		}
+	}
+
+	err = frame.Flush()
+	if err != nil {
+		return fmt.Errorf("failed to update pen: %v", err)
+	}
+	p.inProximity = true
+	p.last = state
+	return nil
+}
+
+func (p *penState) proximityOut() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.sendProximityOut()
+}
+
+// sendProximityOut will lift the pen, release its buttons and move it out of proximity within a single frame. The
+// caller needs to hold the mutex.
+func (p *penState) sendProximityOut() error {
+	if !p.inProximity {
+		return nil
+	}
+
+	tool, err := p.last.Tool.code()
+	if err != nil {
+		return err
+	}
+	frame := newFrame(p.deviceFile)
+	if p.last.Pressure > 0 {
+		frame.Emit(evAbs, AbsPressure, 0)
+		frame.Emit(evKey, ButtonTouch, btnStateReleased)
+	}
+	if p.last.Stylus {
+		frame.Emit(evKey, ButtonStylus, btnStateReleased)
+	}
+	if p.last.Stylus2 {
+		frame.Emit(evKey, ButtonStylus2, btnStateReleased)
+	}
+	frame.Emit(evKey, tool, btnStateReleased)
+
+	err = frame.Flush()
+	if err != nil {
+		return fmt.Errorf("failed to move pen out of proximity: %v", err)
+	}
+	p.inProximity = false
+	p.last = PenState{}
+	return nil
+}
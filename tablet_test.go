@@ -0,0 +1,220 @@
+package uinput
+
+import "testing"
+
+func TestTabletRegistersPenCapabilities(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+	dev, err := CreateTablet(fake.Path(), []byte("Test Tablet"), TabletConfig{Width: 30000, Height: 20000, Resolution: 200})
+	if err != nil {
+		t.Fatalf("Failed to create the virtual tablet. Last error was: %s\n", err)
+	}
+	fd := fake.Device("Test Tablet")
+	defer dev.Close()
+
+	if !fd.HasProperty(PropPointer) || fd.HasProperty(PropDirect) {
+		t.Fatalf("Expected the tablet to declare INPUT_PROP_POINTER only")
+	}
+	fd.ExpectCodes(t, EvKey, ButtonToolPen, ButtonToolRubber, ButtonTouch, ButtonStylus, ButtonStylus2)
+	fd.ExpectCodes(t, EvAbs, AbsX, AbsY, AbsPressure, AbsDistance, AbsTiltX, AbsTiltY)
+
+	axis, ok := fd.Axis(AbsX)
+	if !ok || axis.Max != 30000 || axis.Resolution != 200 {
+		t.Fatalf("Expected the x-axis to range from 0 to 30000 with a resolution of 200, but got %+v", axis)
+	}
+	axis, ok = fd.Axis(AbsPressure)
+	if !ok || axis.Max != defaultTabletMaxPressure {
+		t.Fatalf("Expected the default pressure range, but got %+v", axis)
+	}
+}
+
+func TestDirectTabletDeclaresDirectProperty(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+	dev, err := CreateTablet(fake.Path(), []byte("Test Tablet"), TabletConfig{Width: 30000, Height: 20000, Resolution: 200, Direct: true})
+	if err != nil {
+		t.Fatalf("Failed to create the virtual tablet. Last error was: %s\n", err)
+	}
+	fd := fake.Device("Test Tablet")
+	defer dev.Close()
+
+	if !fd.HasProperty(PropDirect) || fd.HasProperty(PropPointer) {
+		t.Fatalf("Expected the tablet to declare INPUT_PROP_DIRECT only")
+	}
+}
+
+func TestTabletPenStrokeWithProximity(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+	dev, err := CreateTablet(fake.Path(), []byte("Test Tablet"), TabletConfig{Width: 30000, Height: 20000, Resolution: 200})
+	if err != nil {
+		t.Fatalf("Failed to create the virtual tablet. Last error was: %s\n", err)
+	}
+	fd := fake.Device("Test Tablet")
+	defer dev.Close()
+
+	// hovering brings the pen into proximity, sending all axes
+	err = dev.Update(PenState{X: 100, Y: 200, Distance: 10, TiltX: 20})
+	if err != nil {
+		t.Fatalf("Failed to hover the pen. Last error was: %s\n", err)
+	}
+	fd.ExpectEvents(t,
+		Event{Type: EvAbs, Code: AbsX, Value: 100},
+		Event{Type: EvAbs, Code: AbsY, Value: 200},
+		Event{Type: EvAbs, Code: AbsPressure, Value: 0},
+		Event{Type: EvAbs, Code: AbsDistance, Value: 10},
+		Event{Type: EvAbs, Code: AbsTiltX, Value: 20},
+		Event{Type: EvAbs, Code: AbsTiltY, Value: 0},
+		Event{Type: EvKey, Code: ButtonToolPen, Value: 1},
+		Event{Type: EvSyn, Code: SynReport})
+	if !dev.InProximity() {
+		t.Fatalf("Expected the pen to be in proximity")
+	}
+
+	err = dev.Update(PenState{X: 120, Y: 200, Pressure: 1000, TiltX: 20, Stylus: true})
+	if err != nil {
+		t.Fatalf("Failed to put the pen down. Last error was: %s\n", err)
+	}
+	fd.ExpectEvents(t,
+		Event{Type: EvAbs, Code: AbsX, Value: 120},
+		Event{Type: EvAbs, Code: AbsPressure, Value: 1000},
+		Event{Type: EvAbs, Code: AbsDistance, Value: 0},
+		Event{Type: EvKey, Code: ButtonTouch, Value: 1},
+		Event{Type: EvKey, Code: ButtonStylus, Value: 1},
+		Event{Type: EvSyn, Code: SynReport})
+
+	err = dev.ProximityOut()
+	if err != nil {
+		t.Fatalf("Failed to move the pen out of proximity. Last error was: %s\n", err)
+	}
+	fd.ExpectEvents(t,
+		Event{Type: EvAbs, Code: AbsPressure, Value: 0},
+		Event{Type: EvKey, Code: ButtonTouch, Value: 0},
+		Event{Type: EvKey, Code: ButtonStylus, Value: 0},
+		Event{Type: EvKey, Code: ButtonToolPen, Value: 0},
+		Event{Type: EvSyn, Code: SynReport})
+	if dev.InProximity() {
+		t.Fatalf("Expected the pen to be out of proximity")
+	}
+
+	err = dev.ProximityOut()
+	if err != nil {
+		t.Fatalf("Failed to move the pen out of proximity twice. Last error was: %s\n", err)
+	}
+	fd.ExpectNoEvents(t)
+}
+
+func TestTabletSwitchingToolsLeavesProximityFirst(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+	dev, err := CreateTablet(fake.Path(), []byte("Test Tablet"), TabletConfig{Width: 30000, Height: 20000, Resolution: 200})
+	if err != nil {
+		t.Fatalf("Failed to create the virtual tablet. Last error was: %s\n", err)
+	}
+	fd := fake.Device("Test Tablet")
+	defer dev.Close()
+
+	err = dev.Update(PenState{X: 100, Y: 100, Distance: 5})
+	if err != nil {
+		t.Fatalf("Failed to hover the pen. Last error was: %s\n", err)
+	}
+	fd.ClearEvents()
+
+	err = dev.Update(PenState{Tool: ToolEraser, X: 100, Y: 100, Distance: 5})
+	if err != nil {
+		t.Fatalf("Failed to switch to the eraser. Last error was: %s\n", err)
+	}
+	frames := splitFrames(fd.Events())
+	if len(frames) != 2 {
+		t.Fatalf("Expected 2 frames (proximity out, proximity in), but got %d: %v", len(frames), frames)
+	}
+	expectFrame(t, frames[0], Event{Type: EvKey, Code: ButtonToolPen, Value: 0})
+	last := frames[1][len(frames[1])-1]
+	if last != (Event{Type: EvKey, Code: ButtonToolRubber, Value: 1}) {
+		t.Fatalf("Expected the eraser to enter proximity, but got %v", frames[1])
+	}
+}
+
+func TestTabletClampsValues(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+	dev, err := CreateTablet(fake.Path(), []byte("Test Tablet"), TabletConfig{Width: 1000, Height: 1000, Resolution: 10, MaxPressure: 100})
+	if err != nil {
+		t.Fatalf("Failed to create the virtual tablet. Last error was: %s\n", err)
+	}
+	fd := fake.Device("Test Tablet")
+	defer dev.Close()
+
+	err = dev.Update(PenState{X: 2000, Y: -5, Pressure: 500, TiltX: 90, TiltY: -90})
+	if err != nil {
+		t.Fatalf("Failed to update the pen. Last error was: %s\n", err)
+	}
+	fd.ExpectEvents(t,
+		Event{Type: EvAbs, Code: AbsX, Value: 1000},
+		Event{Type: EvAbs, Code: AbsY, Value: 0},
+		Event{Type: EvAbs, Code: AbsPressure, Value: 100},
+		Event{Type: EvAbs, Code: AbsDistance, Value: 0},
+		Event{Type: EvAbs, Code: AbsTiltX, Value: tabletMaxTilt},
+		Event{Type: EvAbs, Code: AbsTiltY, Value: tabletMinTilt},
+		Event{Type: EvKey, Code: ButtonToolPen, Value: 1},
+		Event{Type: EvKey, Code: ButtonTouch, Value: 1},
+		Event{Type: EvSyn, Code: SynReport})
+}
+
+func TestTabletCloseMovesPenOutOfProximity(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+	dev, err := CreateTablet(fake.Path(), []byte("Test Tablet"), TabletConfig{Width: 1000, Height: 1000, Resolution: 10})
+	if err != nil {
+		t.Fatalf("Failed to create the virtual tablet. Last error was: %s\n", err)
+	}
+	fd := fake.Device("Test Tablet")
+
+	err = dev.Update(PenState{X: 10, Y: 10, Pressure: 50})
+	if err != nil {
+		t.Fatalf("Failed to update the pen. Last error was: %s\n", err)
+	}
+	fd.ClearEvents()
+
+	err = dev.Close()
+	if err != nil {
+		t.Fatalf("Failed to close device. Last error was: %s\n", err)
+	}
+	fd.ExpectEvents(t,
+		Event{Type: EvAbs, Code: AbsPressure, Value: 0},
+		Event{Type: EvKey, Code: ButtonTouch, Value: 0},
+		Event{Type: EvKey, Code: ButtonToolPen, Value: 0},
+		Event{Type: EvSyn, Code: SynReport})
+
+	err = dev.Update(PenState{X: 10, Y: 10})
+	if err == nil {
+		t.Fatalf("Expected Update to fail on a closed device")
+	}
+}
+
+func TestTabletCreationFailsWithoutResolution(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	_, err := CreateTablet(fake.Path(), []byte("Test Tablet"), TabletConfig{Width: 1000, Height: 1000})
+	if err == nil {
+		t.Fatalf("Expected creation to fail without a resolution")
+	}
+}
+
+func TestTabletUpdateFailsForUnknownTool(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+	dev, err := CreateTablet(fake.Path(), []byte("Test Tablet"), TabletConfig{Width: 1000, Height: 1000, Resolution: 10})
+	if err != nil {
+		t.Fatalf("Failed to create the virtual tablet. Last error was: %s\n", err)
+	}
+	fd := fake.Device("Test Tablet")
+	defer dev.Close()
+
+	err = dev.Update(PenState{Tool: PenTool(5)})
+	if err == nil {
+		t.Fatalf("Expected Update to fail for an unknown tool")
+	}
+	fd.ExpectNoEvents(t)
+}
@@ -0,0 +1,288 @@
+package uinput
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sync"
+)
+
+// limits of a tablet pad, following the pads of Wacom tablets
+const (
+	maxTabletPadButtons     = 10
+	defaultTabletPadButtons = 8
+	// rings range from 0 to 71, 0 meaning that the finger was lifted
+	tabletPadRingMax = 71
+	// strips report their position as a single bit (1 to 1 << 12), 0 meaning that the finger was lifted
+	tabletPadStripPositions = 13
+)
+
+// codes of the rings and strips of a tablet pad (in this order)
+var (
+	tabletPadRings  = []uint16{AbsWheel, AbsThrottle}
+	tabletPadStrips = []uint16{AbsRX, AbsRY}
+)
+
+// TabletPadConfig describes the controls of a tablet pad. The zero value selects eight express keys and neither rings
+// nor strips.
+type TabletPadConfig struct {
+	// Buttons is the number of express keys (up to 10), which are reported as BTN_0 to BTN_9. Defaults to 8.
+	Buttons int
+	// Rings is the number of touch rings (up to 2).
+	Rings int
+	// Strips is the number of touch strips (up to 2).
+	Strips int
+}
+
+// A TabletPad is the pad of a drawing tablet, providing express keys, touch rings and touch strips that desktop
+// environments map onto shortcuts. Like real pads, it is a device of its own, separate from the pen (see Tablet).
+type TabletPad interface {
+	// ExpressKeyClick will press and release the express key with the given index (starting at 0).
+	ExpressKeyClick(key int) error
+
+	// ExpressKeyPress will press the express key with the given index. The key will not be released until
+	// ExpressKeyRelease is invoked.
+	ExpressKeyPress(key int) error
+
+	// ExpressKeyRelease will release the express key with the given index.
+	ExpressKeyRelease(key int) error
+
+	// RingMove will report the position of a finger on the ring with the given index. The position is given as a
+	// fraction of a full turn (0 to 1, clockwise), starting at the left-most position of the ring.
+	RingMove(ring int, position float64) error
+
+	// RingRelease will report that the finger was lifted off the ring with the given index.
+	RingRelease(ring int) error
+
+	// StripMove will report the position of a finger on the strip with the given index, from 0 (top or left) to 1
+	// (bottom or right).
+	StripMove(strip int, position float64) error
+
+	// StripRelease will report that the finger was lifted off the strip with the given index.
+	StripRelease(strip int) error
+
+	// FetchSyspath will return the syspath to the device file.
+	FetchSyspath() (string, error)
+
+	EventEmitter
+
+	EventReceiver
+
+	Releaser
+
+	io.Closer
+}
+
+type vTabletPad struct {
+	name       []byte
+	deviceFile uinputFile
+	config     TabletPadConfig
+	touches    *padTouches
+	reader     *eventReader
+}
+
+// padTouches holds the values last sent for the rings and strips, so that fingers can be lifted on release.
+type padTouches struct {
+	mutex  sync.Mutex
+	values map[uint16]int32
+}
+
+// CreateTabletPad will create a new tablet pad with the given controls.
+func CreateTabletPad(path string, name []byte, config TabletPadConfig, options ...DeviceOption) (TabletPad, error) {
+	err := validateDevicePath(path)
+	if err != nil {
+		return nil, err
+	}
+	err = validateUinputName(name)
+	if err != nil {
+		return nil, err
+	}
+	config, err = config.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+
+	fd, err := createDevice(path, name, config.spec(), newDeviceOptions(options))
+	if err != nil {
+		return nil, err
+	}
+
+	return vTabletPad{
+		name:       name,
+		deviceFile: fd,
+		config:     config,
+		touches:    &padTouches{values: make(map[uint16]int32)},
+		reader:     startEventReader(fd, nil)}, nil
+}
+
+func (config TabletPadConfig) withDefaults() (TabletPadConfig, error) {
+	if config.Buttons < 0 || config.Buttons > maxTabletPadButtons {
+		return config, fmt.Errorf("number of express keys must be within 0 and %d, but is %d",
+			maxTabletPadButtons, config.Buttons)
+	}
+	if config.Rings < 0 || config.Rings > len(tabletPadRings) {
+		return config, fmt.Errorf("number of rings must be within 0 and %d, but is %d",
+			len(tabletPadRings), config.Rings)
+	}
+	if config.Strips < 0 || config.Strips > len(tabletPadStrips) {
+		return config, fmt.Errorf("number of strips must be within 0 and %d, but is %d",
+			len(tabletPadStrips), config.Strips)
+	}
+	if config.Buttons == 0 {
+		config.Buttons = defaultTabletPadButtons
+	}
+	return config, nil
+}
+
+func (config TabletPadConfig) spec() DeviceSpec {
+	// like the pads of Wacom tablets, BTN_STYLUS and the x and y-axis are declared (but never sent), since udev
+	// relies on them to classify the device as a tablet pad
+	keys := []uint16{ButtonStylus}
+	for i := 0; i < config.Buttons; i++ {
+		keys = append(keys, uint16(Button0+i))
+	}
+	axes := []AxisConfig{
+		{Code: AbsX, Min: 0, Max: 1},
+		{Code: AbsY, Min: 0, Max: 1},
+	}
+	for _, code := range tabletPadRings[:config.Rings] {
+		axes = append(axes, AxisConfig{Code: code, Min: 0, Max: tabletPadRingMax})
+	}
+	for _, code := range tabletPadStrips[:config.Strips] {
+		axes = append(axes, AxisConfig{Code: code, Min: 0, Max: 1 << (tabletPadStripPositions - 1)})
+	}
+
+	return DeviceSpec{
+		ID:      InputID{Bustype: BusUsb, Vendor: 0x4711, Product: 0x081b, Version: 1},
+		Keys:    keys,
+		AbsAxes: axes,
+	}
+}
+
+func (vPad vTabletPad) ExpressKeyClick(key int) error {
+	err := vPad.ExpressKeyPress(key)
+	if err != nil {
+		return fmt.Errorf("failed to issue the click event: %v", err)
+	}
+	return vPad.ExpressKeyRelease(key)
+}
+
+func (vPad vTabletPad) ExpressKeyPress(key int) error {
+	return vPad.sendExpressKeyEvent(key, btnStatePressed)
+}
+
+func (vPad vTabletPad) ExpressKeyRelease(key int) error {
+	return vPad.sendExpressKeyEvent(key, btnStateReleased)
+}
+
+func (vPad vTabletPad) sendExpressKeyEvent(key int, btnState int) error {
+	if key < 0 || key >= vPad.config.Buttons {
+		return fmt.Errorf("express key %d is not supported", key)
+	}
+	return sendBtnEvent(vPad.deviceFile, []int{Button0 + key}, btnState)
+}
+
+func (vPad vTabletPad) RingMove(ring int, position float64) error {
+	if ring < 0 || ring >= vPad.config.Rings {
+		return fmt.Errorf("ring %d is not supported", ring)
+	}
+	// positions wrap around and are mapped onto 1 to 71, since 0 is reserved for lifting the finger
+	position -= math.Floor(position)
+	value := 1 + int32(position*tabletPadRingMax)
+	if value > tabletPadRingMax {
+		value = tabletPadRingMax
+	}
+	return vPad.sendTouchEvent(tabletPadRings[ring], value)
+}
+
+func (vPad vTabletPad) RingRelease(ring int) error {
+	if ring < 0 || ring >= vPad.config.Rings {
+		return fmt.Errorf("ring %d is not supported", ring)
+	}
+	return vPad.sendTouchEvent(tabletPadRings[ring], 0)
+}
+
+func (vPad vTabletPad) StripMove(strip int, position float64) error {
+	if strip < 0 || strip >= vPad.config.Strips {
+		return fmt.Errorf("strip %d is not supported", strip)
+	}
+	if position < 0 {
+		position = 0
+	} else if position > 1 {
+		position = 1
+	}
+	bit := uint(math.Round(position * (tabletPadStripPositions - 1)))
+	return vPad.sendTouchEvent(tabletPadStrips[strip], 1<<bit)
+}
+
+func (vPad vTabletPad) StripRelease(strip int) error {
+	if strip < 0 || strip >= vPad.config.Strips {
+		return fmt.Errorf("strip %d is not supported", strip)
+	}
+	return vPad.sendTouchEvent(tabletPadStrips[strip], 0)
+}
+
+func (vPad vTabletPad) sendTouchEvent(code uint16, value int32) error {
+	vPad.touches.mutex.Lock()
+	defer vPad.touches.mutex.Unlock()
+
+	err := emitEvent(vPad.deviceFile, evAbs, code, value)
+	if err != nil {
+		return fmt.Errorf("failed to send pad event: %v", err)
+	}
+	vPad.touches.values[code] = value
+	return nil
+}
+
+func (vPad vTabletPad) FetchSyspath() (string, error) {
+	return fetchSyspath(vPad.deviceFile)
+}
+
+// Emit will send a single raw event to the device, immediately followed by a SYN_REPORT.
+func (vPad vTabletPad) Emit(evType uint16, code uint16, value int32) error {
+	return emitEvent(vPad.deviceFile, evType, code, value)
+}
+
+// NewFrame will create an empty frame that may be used to send multiple events at once.
+func (vPad vTabletPad) NewFrame() *Frame {
+	return newFrame(vPad.deviceFile)
+}
+
+// SetEventHandler registers a handler that is invoked for every event sent to the device.
+func (vPad vTabletPad) SetEventHandler(handler EventHandler) {
+	vPad.reader.setHandler(handler)
+}
+
+// ReleaseAll will lift the fingers off all rings and strips and release all express keys that are currently held
+// down.
+func (vPad vTabletPad) ReleaseAll() error {
+	err := vPad.releaseTouches()
+	if err != nil {
+		return err
+	}
+	return releaseAll(vPad.deviceFile)
+}
+
+func (vPad vTabletPad) Close() error {
+	_ = vPad.releaseTouches()
+	return closeDevice(vPad.deviceFile)
+}
+
+// releaseTouches will lift the fingers off all rings and strips within a single frame.
+func (vPad vTabletPad) releaseTouches() error {
+	vPad.touches.mutex.Lock()
+	defer vPad.touches.mutex.Unlock()
+
+	frame := newFrame(vPad.deviceFile)
+	for _, code := range append(append([]uint16(nil), tabletPadRings...), tabletPadStrips...) {
+		if vPad.touches.values[code] != 0 {
+			frame.Emit(evAbs, code, 0)
+		}
+	}
+	err := frame.Flush()
+	if err != nil {
+		return fmt.Errorf("failed to lift fingers off rings and strips: %v", err)
+	}
+	vPad.touches.values = make(map[uint16]int32)
+	return nil
+}
@@ -0,0 +1,163 @@
+package uinput
+
+import "testing"
+
+func TestTabletPadRegistersControls(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+	dev, err := CreateTabletPad(fake.Path(), []byte("Test Tablet Pad"), TabletPadConfig{Buttons: 4, Rings: 1, Strips: 2})
+	if err != nil {
+		t.Fatalf("Failed to create the virtual tablet pad. Last error was: %s\n", err)
+	}
+	fd := fake.Device("Test Tablet Pad")
+	defer dev.Close()
+
+	fd.ExpectCodes(t, EvKey, Button0, Button1, Button2, Button3, ButtonStylus)
+	fd.ExpectCodes(t, EvAbs, AbsX, AbsY, AbsRX, AbsRY, AbsWheel)
+
+	axis, ok := fd.Axis(AbsWheel)
+	if !ok || axis.Max != 71 {
+		t.Fatalf("Expected the ring to range from 0 to 71, but got %+v", axis)
+	}
+	axis, ok = fd.Axis(AbsRX)
+	if !ok || axis.Max != 4096 {
+		t.Fatalf("Expected the strip to range from 0 to 4096, but got %+v", axis)
+	}
+}
+
+func TestTabletPadExpressKeys(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+	dev, err := CreateTabletPad(fake.Path(), []byte("Test Tablet Pad"), TabletPadConfig{})
+	if err != nil {
+		t.Fatalf("Failed to create the virtual tablet pad. Last error was: %s\n", err)
+	}
+	fd := fake.Device("Test Tablet Pad")
+	defer dev.Close()
+
+	err = dev.ExpressKeyClick(7)
+	if err != nil {
+		t.Fatalf("Failed to click express key. Last error was: %s\n", err)
+	}
+	fd.ExpectEvents(t,
+		Event{Type: EvKey, Code: Button7, Value: 1},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvKey, Code: Button7, Value: 0},
+		Event{Type: EvSyn, Code: SynReport})
+
+	err = dev.ExpressKeyPress(8)
+	if err == nil {
+		t.Fatalf("Expected pressing an express key that does not exist to fail")
+	}
+	fd.ExpectNoEvents(t)
+}
+
+func TestTabletPadRingAndStripPositions(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+	dev, err := CreateTabletPad(fake.Path(), []byte("Test Tablet Pad"), TabletPadConfig{Rings: 2, Strips: 1})
+	if err != nil {
+		t.Fatalf("Failed to create the virtual tablet pad. Last error was: %s\n", err)
+	}
+	fd := fake.Device("Test Tablet Pad")
+	defer dev.Close()
+
+	for _, step := range []struct {
+		send     func() error
+		code     uint16
+		expected int32
+	}{
+		{func() error { return dev.RingMove(0, 0) }, AbsWheel, 1},
+		{func() error { return dev.RingMove(0, 0.5) }, AbsWheel, 36},
+		{func() error { return dev.RingMove(1, 1.25) }, AbsThrottle, 18},
+		{func() error { return dev.RingRelease(0) }, AbsWheel, 0},
+		{func() error { return dev.StripMove(0, 0) }, AbsRX, 1},
+		{func() error { return dev.StripMove(0, 1) }, AbsRX, 4096},
+		{func() error { return dev.StripRelease(0) }, AbsRX, 0},
+	} {
+		err := step.send()
+		if err != nil {
+			t.Fatalf("Failed to send pad event. Last error was: %s\n", err)
+		}
+		fd.ExpectEvents(t,
+			Event{Type: EvAbs, Code: step.code, Value: step.expected},
+			Event{Type: EvSyn, Code: SynReport})
+	}
+
+	err = dev.StripMove(1, 0.5)
+	if err == nil {
+		t.Fatalf("Expected moving on a strip that does not exist to fail")
+	}
+}
+
+func TestTabletPadReleaseAllLiftsFingers(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+	dev, err := CreateTabletPad(fake.Path(), []byte("Test Tablet Pad"), TabletPadConfig{Rings: 1, Strips: 1})
+	if err != nil {
+		t.Fatalf("Failed to create the virtual tablet pad. Last error was: %s\n", err)
+	}
+	fd := fake.Device("Test Tablet Pad")
+	defer dev.Close()
+
+	err = dev.RingMove(0, 0.25)
+	if err != nil {
+		t.Fatalf("Failed to move on the ring. Last error was: %s\n", err)
+	}
+	err = dev.StripMove(0, 0.5)
+	if err != nil {
+		t.Fatalf("Failed to move on the strip. Last error was: %s\n", err)
+	}
+	err = dev.ExpressKeyPress(0)
+	if err != nil {
+		t.Fatalf("Failed to press express key. Last error was: %s\n", err)
+	}
+	fd.ClearEvents()
+
+	err = dev.ReleaseAll()
+	if err != nil {
+		t.Fatalf("Failed to release all. Last error was: %s\n", err)
+	}
+	fd.ExpectEvents(t,
+		Event{Type: EvAbs, Code: AbsWheel, Value: 0},
+		Event{Type: EvAbs, Code: AbsRX, Value: 0},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvKey, Code: Button0, Value: 0},
+		Event{Type: EvSyn, Code: SynReport})
+
+	err = dev.ReleaseAll()
+	if err != nil {
+		t.Fatalf("Failed to release all twice. Last error was: %s\n", err)
+	}
+	fd.ExpectNoEvents(t)
+}
+
+func TestTabletPadCreationFailsForTooManyControls(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	for _, config := range []TabletPadConfig{{Buttons: 11}, {Rings: 3}, {Strips: -1}} {
+		_, err := CreateTabletPad(fake.Path(), []byte("Test Tablet Pad"), config)
+		if err == nil {
+			t.Fatalf("Expected creation to fail for %+v", config)
+		}
+	}
+}
+
+func TestTabletPadFailsOnClosedDevice(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+	dev, err := CreateTabletPad(fake.Path(), []byte("Test Tablet Pad"), TabletPadConfig{Rings: 1})
+	if err != nil {
+		t.Fatalf("Failed to create the virtual tablet pad. Last error was: %s\n", err)
+	}
+	err = dev.Close()
+	if err != nil {
+		t.Fatalf("Failed to close device. Last error was: %s\n", err)
+	}
+
+	err = dev.RingMove(0, 0.5)
+	if err == nil {
+		t.Fatalf("Expected RingMove to fail on a closed device")
+	}
+}
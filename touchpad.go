@@ -3,7 +3,6 @@ package uinput
 import (
 	"fmt"
 	"io"
-	"os"
 )
 
 // A TouchPad is an input device that uses absolute axis events, meaning that you can specify
@@ -42,17 +41,24 @@ type TouchPad interface {
 	// FetchSyspath will return the syspath to the device file.
 	FetchSyspath() (string, error)
 
+	EventEmitter
+
+	EventReceiver
+
+	Releaser
+
 	io.Closer
 }
 
 type vTouchPad struct {
 	name       []byte
-	deviceFile *os.File
+	deviceFile uinputFile
+	reader     *eventReader
 }
 
 // CreateTouchPad will create a new touchpad device. note that you will need to define the x and y-axis boundaries
 // (min and max) within which the cursor maybe moved around.
-func CreateTouchPad(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32) (TouchPad, error) {
+func CreateTouchPad(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32, options ...DeviceOption) (TouchPad, error) {
 	err := validateDevicePath(path)
 	if err != nil {
 		return nil, err
@@ -62,12 +68,12 @@ func CreateTouchPad(path string, name []byte, minX int32, maxX int32, minY int32
 		return nil, err
 	}
 
-	fd, err := createTouchPad(path, name, minX, maxX, minY, maxY)
+	fd, err := createTouchPad(path, name, minX, maxX, minY, maxY, newDeviceOptions(options, PropPointer))
 	if err != nil {
 		return nil, err
 	}
 
-	return vTouchPad{name: name, deviceFile: fd}, nil
+	return vTouchPad{name: name, deviceFile: fd, reader: startEventReader(fd, nil)}, nil
 }
 
 func (vTouch vTouchPad) MoveTo(x int32, y int32) error {
@@ -122,11 +128,31 @@ func (vTouch vTouchPad) TouchUp() error {
 	return sendBtnEvent(vTouch.deviceFile, []int{evBtnTouch}, btnStateReleased)
 }
 
+// Emit will send a single raw event to the device, immediately followed by a SYN_REPORT.
+func (vTouch vTouchPad) Emit(evType uint16, code uint16, value int32) error {
+	return emitEvent(vTouch.deviceFile, evType, code, value)
+}
+
+// NewFrame will create an empty frame that may be used to send multiple events at once.
+func (vTouch vTouchPad) NewFrame() *Frame {
+	return newFrame(vTouch.deviceFile)
+}
+
+// SetEventHandler registers a handler that is invoked for every event sent to the device.
+func (vTouch vTouchPad) SetEventHandler(handler EventHandler) {
+	vTouch.reader.setHandler(handler)
+}
+
+// ReleaseAll will release all keys, buttons and touch contacts that are currently held down.
+func (vTouch vTouchPad) ReleaseAll() error {
+	return releaseAll(vTouch.deviceFile)
+}
+
 func (vTouch vTouchPad) Close() error {
 	return closeDevice(vTouch.deviceFile)
 }
 
-func createTouchPad(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32) (fd *os.File, err error) {
+func createTouchPad(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32, options deviceOptions) (fd uinputFile, err error) {
 	deviceFile, err := createDeviceFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("could not create absolute axis input device: %v", err)
@@ -161,27 +187,22 @@ func createTouchPad(path string, name []byte, minX int32, maxX int32, minY int32
 		}
 	}
 
-	var absMin [absSize]int32
-	absMin[absX] = minX
-	absMin[absY] = minY
-
-	var absMax [absSize]int32
-	absMax[absX] = maxX
-	absMax[absY] = maxY
-
 	return createUsbDevice(deviceFile,
-		uinputUserDev{
+		uinputSetup{
 			Name: toUinputName(name),
 			ID: inputID{
 				Bustype: busUsb,
 				Vendor:  0x4711,
 				Product: 0x0817,
-				Version: 1},
-			Absmin: absMin,
-			Absmax: absMax})
+				Version: 1}},
+		[]uinputAbsSetup{
+			absAxis(absX, minX, maxX),
+			absAxis(absY, minY, maxY),
+		},
+		options)
 }
 
-func sendAbsEvent(deviceFile *os.File, xPos int32, yPos int32) error { // TODO: Perhaps move this to a more generic function? This conflicts with the gamepad ABS events which only have one value.
+func sendAbsEvent(deviceFile uinputFile, xPos int32, yPos int32) error { // TODO: Perhaps move this to a more generic function? This conflicts with the gamepad ABS events which only have one value.
 	var ev [2]inputEvent
 	ev[0].Type = evAbs
 	ev[0].Code = absX
@@ -282,3 +282,19 @@ func TestTouchPadSyspath(t *testing.T) {
 
 	t.Logf("Syspath: %s", sysPath)
 }
+
+func TestTouchPadPropertiesMayBeAdded(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	absDev, err := CreateTouchPad(fake.Path(), []byte("Test TouchPad"), 0, 1024, 0, 768, WithProperties(PropButtonpad))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual touch pad. Last error was: %s\n", err)
+	}
+	defer absDev.Close()
+
+	dev := fake.Device("Test TouchPad")
+	if !dev.HasProperty(PropPointer) || !dev.HasProperty(PropButtonpad) {
+		t.Fatalf("Expected the touch pad to declare INPUT_PROP_POINTER and INPUT_PROP_BUTTONPAD")
+	}
+}
@@ -79,16 +79,21 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"reflect"
+	"runtime"
 	"syscall"
 	"time"
-	"unsafe"
 )
 
 func validateDevicePath(path string) error {
 	if path == "" {
 		return errors.New("device path must not be empty")
 	}
+	if lookupFake(path) != nil {
+		return nil
+	}
 	_, err := os.Stat(path)
 	return err
 }
@@ -109,15 +114,18 @@ func toUinputName(name []byte) (uinputName [uinputMaxNameSize]byte) {
 	return fixedSizeName
 }
 
-func createDeviceFile(path string) (fd *os.File, err error) {
-	deviceFile, err := os.OpenFile(path, syscall.O_WRONLY|syscall.O_NONBLOCK, 0660)
+func createDeviceFile(path string) (fd uinputFile, err error) {
+	if fake := lookupFake(path); fake != nil {
+		return fake.open(), nil
+	}
+	deviceFile, err := os.OpenFile(path, syscall.O_RDWR|syscall.O_NONBLOCK, 0660)
 	if err != nil {
 		return nil, errors.New("could not open device file")
 	}
-	return deviceFile, err
+	return osFile{deviceFile}, err
 }
 
-func registerDevice(deviceFile *os.File, evType uintptr) error {
+func registerDevice(deviceFile uinputFile, evType uintptr) error {
 	err := ioctl(deviceFile, uiSetEvBit, evType)
 	if err != nil {
 		defer deviceFile.Close()
@@ -130,17 +138,27 @@ func registerDevice(deviceFile *os.File, evType uintptr) error {
 	return nil
 }
 
-func createUsbDevice(deviceFile *os.File, dev uinputUserDev) (fd *os.File, err error) {
-	buf := new(bytes.Buffer)
-	err = binary.Write(buf, binary.LittleEndian, dev)
+func createUsbDevice(deviceFile uinputFile, setup uinputSetup, axes []uinputAbsSetup, options deviceOptions) (fd uinputFile, err error) {
+	err = options.validate()
+	if err == nil {
+		err = registerProperties(deviceFile, options.properties)
+	}
+	if err == nil {
+		err = options.apply(deviceFile, &setup)
+	}
 	if err != nil {
 		_ = deviceFile.Close()
-		return nil, fmt.Errorf("failed to write user device buffer: %v", err)
+		return nil, err
+	}
+
+	if supportsDevSetup(deviceFile) {
+		err = setupDevice(deviceFile, setup, axes)
+	} else {
+		err = writeUserDev(deviceFile, toUserDev(setup, axes))
 	}
-	_, err = deviceFile.Write(buf.Bytes())
 	if err != nil {
 		_ = deviceFile.Close()
-		return nil, fmt.Errorf("failed to write uidev struct to device file: %v", err)
+		return nil, err
 	}
 
 	err = ioctl(deviceFile, uiDevCreate, uintptr(0))
@@ -149,12 +167,128 @@ func createUsbDevice(deviceFile *os.File, dev uinputUserDev) (fd *os.File, err e
 		return nil, fmt.Errorf("failed to create device: %v", err)
 	}
 
-	time.Sleep(time.Millisecond * 200)
+	// give udev some time to pick up the new device (this is not necessary for fake devices)
+	if _, ok := deviceFile.(*fakeFile); !ok {
+		time.Sleep(time.Millisecond * 200)
+	}
+
+	return newTrackingFile(deviceFile), nil
+}
+
+// supportsDevSetup checks whether the kernel knows about UI_DEV_SETUP and UI_ABS_SETUP. Older kernels (prior to 4.5)
+// only support the legacy way of writing a uinput_user_dev struct to the device file.
+func supportsDevSetup(deviceFile uinputFile) bool {
+	var version uint32
+	err := ioctl(deviceFile, uiGetVersion, &version)
+	return err == nil && version >= uinputVersionDevSetup
+}
+
+func setupDevice(deviceFile uinputFile, setup uinputSetup, axes []uinputAbsSetup) error {
+	for _, axis := range axes {
+		err := ioctl(deviceFile, uiAbsSetup, &axis)
+		if err != nil {
+			return fmt.Errorf("failed to set up absolute axis %v: %v", axis.Code, err)
+		}
+	}
+
+	err := ioctl(deviceFile, uiDevSetup, &setup)
+	if err != nil {
+		return fmt.Errorf("failed to set up device: %v", err)
+	}
+	return nil
+}
+
+func writeUserDev(deviceFile uinputFile, dev uinputUserDev) error {
+	buf := new(bytes.Buffer)
+	err := binary.Write(buf, binary.LittleEndian, dev)
+	if err != nil {
+		return fmt.Errorf("failed to write user device buffer: %v", err)
+	}
+	_, err = deviceFile.Write(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to write uidev struct to device file: %v", err)
+	}
+	return nil
+}
+
+// toUserDev converts the device setup into the legacy uinput_user_dev struct. Note that the resolution of
+// absolute axes can not be represented in this struct and will therefore be dropped.
+func toUserDev(setup uinputSetup, axes []uinputAbsSetup) uinputUserDev {
+	dev := uinputUserDev{
+		Name:       setup.Name,
+		ID:         setup.ID,
+		EffectsMax: setup.EffectsMax,
+	}
+	for _, axis := range axes {
+		if axis.Code >= absSize {
+			continue
+		}
+		dev.Absmin[axis.Code] = axis.Absinfo.Minimum
+		dev.Absmax[axis.Code] = axis.Absinfo.Maximum
+		dev.Absfuzz[axis.Code] = axis.Absinfo.Fuzz
+		dev.Absflat[axis.Code] = axis.Absinfo.Flat
+	}
+	return dev
+}
+
+func absAxis(code uint16, min int32, max int32) uinputAbsSetup {
+	return uinputAbsSetup{Code: code, Absinfo: absInfo{Minimum: min, Maximum: max}}
+}
+
+// AxisConfig describes the range and characteristics of an absolute axis (see the Abs* constants in keycodes.go).
+// Flat defines the dead zone around the center of the axis, while values that differ by less than Fuzz
+// are filtered out as noise. The resolution is given in units per millimeter (or units per radian for
+// rotational axes).
+type AxisConfig struct {
+	Code       uint16
+	Min        int32
+	Max        int32
+	Fuzz       int32
+	Flat       int32
+	Resolution int32
+}
+
+func (a AxisConfig) toAbsSetup() uinputAbsSetup {
+	return uinputAbsSetup{
+		Code: a.Code,
+		Absinfo: absInfo{
+			Minimum:    a.Min,
+			Maximum:    a.Max,
+			Fuzz:       a.Fuzz,
+			Flat:       a.Flat,
+			Resolution: a.Resolution,
+		}}
+}
 
-	return deviceFile, err
+// denormalize maps a normalized value (-1.0:1.0) onto the range of the axis. If the range includes zero, a value of 0
+// is mapped onto zero and each direction is scaled separately, so that asymmetric ranges like -32768:32767 are
+// fully covered.
+func (a AxisConfig) denormalize(value float32) int32 {
+	if value > 1 {
+		value = 1
+	} else if value < -1 {
+		value = -1
+	}
+	if a.Min < 0 && a.Max > 0 {
+		if value < 0 {
+			return int32(-value * float32(a.Min))
+		}
+		return int32(value * float32(a.Max))
+	}
+	return a.Min + int32((value+1)/2*float32(a.Max-a.Min))
 }
 
-func closeDevice(deviceFile *os.File) (err error) {
+func toAbsSetups(axes []AxisConfig) []uinputAbsSetup {
+	setups := make([]uinputAbsSetup, 0, len(axes))
+	for _, axis := range axes {
+		setups = append(setups, axis.toAbsSetup())
+	}
+	return setups
+}
+
+func closeDevice(deviceFile uinputFile) (err error) {
+	// make sure that nothing remains held down (the device is destroyed regardless of the outcome)
+	_ = releaseAll(deviceFile)
 	err = releaseDevice(deviceFile)
 	if err != nil {
 		return fmt.Errorf("failed to close device: %v", err)
@@ -162,15 +296,15 @@ func closeDevice(deviceFile *os.File) (err error) {
 	return deviceFile.Close()
 }
 
-func releaseDevice(deviceFile *os.File) (err error) {
+func releaseDevice(deviceFile uinputFile) (err error) {
 	return ioctl(deviceFile, uiDevDestroy, uintptr(0))
 }
 
-func fetchSyspath(deviceFile *os.File) (string, error) {
+func fetchSyspath(deviceFile uinputFile) (string, error) {
 	sysInputDir := "/sys/devices/virtual/input/"
 	// 64 for name + 1 for null byte
 	path := make([]byte, 65)
-	err := ioctl(deviceFile, uiGetSysname, uintptr(unsafe.Pointer(&path[0])))
+	err := ioctl(deviceFile, uiGetSysname, path)
 
 	firstNull := bytes.IndexByte(path, 0)
 	if firstNull != -1 {
@@ -183,7 +317,7 @@ func fetchSyspath(deviceFile *os.File) (string, error) {
 
 // Note that mice and touch pads do have buttons as well. Therefore, this function is used
 // by all currently available devices and resides in the main source file.
-func sendBtnEvent(deviceFile *os.File, keys []int, btnState int) (err error) {
+func sendBtnEvent(deviceFile uinputFile, keys []int, btnState int) (err error) {
 	for _, key := range keys {
 		buf, err := inputEventToBuffer(inputEvent{
 			Time:  syscall.Timeval{Sec: 0, Usec: 0},
@@ -201,7 +335,7 @@ func sendBtnEvent(deviceFile *os.File, keys []int, btnState int) (err error) {
 	return syncEvents(deviceFile)
 }
 
-func syncEvents(deviceFile *os.File) (err error) {
+func syncEvents(deviceFile uinputFile) (err error) {
 	buf, err := inputEventToBuffer(inputEvent{
 		Time:  syscall.Timeval{Sec: 0, Usec: 0},
 		Type:  evSyn,
@@ -223,9 +357,49 @@ func inputEventToBuffer(iev inputEvent) (buffer []byte, err error) {
 	return buf.Bytes(), nil
 }
 
+// uinputFile is the handle of a single uinput device. All interaction with the kernel goes through this interface,
+// which is implemented by osFile for actual device nodes and by fakeFile for devices created using a Fake.
+type uinputFile interface {
+	io.ReadWriteCloser
+
+	// ioctl issues the given request. The argument is either a plain value (uintptr) or a pointer to the struct
+	// (or slice) the request operates on.
+	ioctl(cmd uintptr, arg interface{}) error
+}
+
+func ioctl(deviceFile uinputFile, cmd uintptr, arg interface{}) error {
+	if deviceFile == nil {
+		return os.ErrInvalid
+	}
+	return deviceFile.ioctl(cmd, arg)
+}
+
+// osFile is a uinputFile backed by a device node (usually /dev/uinput).
+type osFile struct {
+	*os.File
+}
+
 // original function taken from: https://github.com/tianon/debian-golang-pty/blob/master/ioctl.go
-func ioctl(deviceFile *os.File, cmd, ptr uintptr) error {
-	_, _, errorCode := syscall.Syscall(syscall.SYS_IOCTL, deviceFile.Fd(), cmd, ptr)
+// The raw connection is used instead of Fd(), since the latter would switch the device file into blocking mode and
+// thereby prevent pending reads from being interrupted when the device is closed.
+func (f osFile) ioctl(cmd uintptr, arg interface{}) error {
+	ptr, ok := arg.(uintptr)
+	if !ok {
+		ptr = reflect.ValueOf(arg).Pointer()
+	}
+
+	conn, err := f.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var errorCode syscall.Errno
+	err = conn.Control(func(fd uintptr) {
+		_, _, errorCode = syscall.Syscall(syscall.SYS_IOCTL, fd, cmd, ptr)
+	})
+	runtime.KeepAlive(arg)
+	if err != nil {
+		return err
+	}
 	if errorCode != 0 {
 		return errorCode
 	}
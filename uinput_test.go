@@ -4,6 +4,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"unsafe"
 )
 
 func TestValidateDevicePathEmptyPathPanics(t *testing.T) {
@@ -40,7 +41,7 @@ func TestFailedDeviceFileCreationGeneratesError(t *testing.T) {
 
 func TestNonExistentDeviceFileCausesError(t *testing.T) {
 	expected := "failed to write uidev struct to device file:"
-	_, err := createUsbDevice(nil, uinputUserDev{})
+	_, err := createUsbDevice(osFile{}, uinputSetup{}, nil, deviceOptions{})
 	if err == nil {
 		t.Fatalf("expected error, but got none")
 	}
@@ -48,3 +49,53 @@ func TestNonExistentDeviceFileCausesError(t *testing.T) {
 		t.Fatalf("got '%v', but expected '%v'", err.Error(), expected)
 	}
 }
+
+func TestLegacyUserDevKeepsAbsoluteAxisRanges(t *testing.T) {
+	axes := []uinputAbsSetup{
+		{Code: absX, Absinfo: absInfo{Minimum: -10, Maximum: 10, Fuzz: 2, Flat: 3, Resolution: 12}},
+		{Code: absSize},
+	}
+	dev := toUserDev(uinputSetup{ID: inputID{Bustype: busUsb}, EffectsMax: 4}, axes)
+
+	if dev.ID.Bustype != busUsb || dev.EffectsMax != 4 {
+		t.Fatalf("Expected device identity to be kept, but got %+v", dev.ID)
+	}
+	if dev.Absmin[absX] != -10 || dev.Absmax[absX] != 10 || dev.Absfuzz[absX] != 2 || dev.Absflat[absX] != 3 {
+		t.Fatalf("Unexpected absolute axis configuration: min=%d max=%d fuzz=%d flat=%d",
+			dev.Absmin[absX], dev.Absmax[absX], dev.Absfuzz[absX], dev.Absflat[absX])
+	}
+}
+
+func TestAbsSetupMatchesKernelLayout(t *testing.T) {
+	if size := unsafe.Sizeof(uinputAbsSetup{}); size != (uiAbsSetup>>16)&0x3fff {
+		t.Fatalf("Expected size of uinputAbsSetup to match ioctl definition, but got %d", size)
+	}
+	if size := unsafe.Sizeof(uinputSetup{}); size != (uiDevSetup>>16)&0x3fff {
+		t.Fatalf("Expected size of uinputSetup to match ioctl definition, but got %d", size)
+	}
+}
+
+func TestAxisDenormalization(t *testing.T) {
+	tests := []struct {
+		axis     AxisConfig
+		value    float32
+		expected int32
+	}{
+		{AxisConfig{Min: -MaximumAxisValue, Max: MaximumAxisValue}, 0.5, 16383},
+		{AxisConfig{Min: -MaximumAxisValue, Max: MaximumAxisValue}, -1, -MaximumAxisValue},
+		{AxisConfig{Min: -32768, Max: 32767}, -1, -32768},
+		{AxisConfig{Min: -32768, Max: 32767}, 0, 0},
+		{AxisConfig{Min: -32768, Max: 32767}, 1, 32767},
+		{AxisConfig{Min: 0, Max: 255}, -1, 0},
+		{AxisConfig{Min: 0, Max: 255}, 1, 255},
+		{AxisConfig{Min: 0, Max: 255}, 2, 255},
+		{AxisConfig{Min: 0, Max: 1000}, 0, 500},
+	}
+
+	for _, test := range tests {
+		actual := test.axis.denormalize(test.value)
+		if actual != test.expected {
+			t.Fatalf("Expected %v to be mapped onto %d for axis %+v, but got %d", test.value, test.expected, test.axis, actual)
+		}
+	}
+}
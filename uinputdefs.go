@@ -8,16 +8,32 @@ const (
 	uiDevCreate       = 0x5501
 	uiDevDestroy      = 0x5502
 	uiDevSetup        = 0x405c5503
+	uiAbsSetup        = 0x401c5504
 	// this is for 64 length buffer to store name
 	// for another length generate using : (len << 16) | 0x8000552C
 	uiGetSysname = 0x8041552c
+	uiGetVersion = 0x8004552d
 	uiSetEvBit   = 0x40045564
 	uiSetKeyBit  = 0x40045565
 
-	uiSetRelBit = 0x40045566
-	uiSetMscBit = 0x40045566
-	uiSetAbsBit = 0x40045567
-	busUsb      = 0x03
+	uiSetRelBit  = 0x40045566
+	uiSetAbsBit  = 0x40045567
+	uiSetMscBit  = 0x40045568
+	uiSetLedBit  = 0x40045569
+	uiSetSndBit  = 0x4004556a
+	uiSetFfBit   = 0x4004556b
+	uiSetSwBit   = 0x4004556d
+	uiSetPropBit = 0x4004556e
+	uiSetPhys    = 0x4008556c // the size of the char pointer as found on 64-bit systems
+	busUsb       = 0x03
+
+	// force feedback requests (sizes of uinput_ff_upload and uinput_ff_erase as found on 64-bit systems)
+	uiBeginFfUpload = 0xc06855c8
+	uiEndFfUpload   = 0x406855c9
+	uiBeginFfErase  = 0xc00c55ca
+	uiEndFfErase    = 0x400c55cb
+	uiFfUpload      = 1
+	uiFfErase       = 2
 )
 
 // input event codes as specified in input-event-codes.h
@@ -27,26 +43,37 @@ const (
 	evRel     = 0x02
 	evAbs     = 0x03
 	evMsc     = 0x04
+	evSw      = 0x05
+	evLed     = 0x11
+	evSnd     = 0x12
+	evRep     = 0x14
+	evFf      = 0x15
+	evUinput  = 0x0101
 	relX      = 0x0
 	relY      = 0x1
 	relHWheel = 0x6
 	relWheel  = 0x8
 	relDial   = 0x7
 
-	absX     = 0x00
-	absY     = 0x01
-	absZ     = 0x02
-	absRX    = 0x03
-	absRY    = 0x04
-	absRZ    = 0x05
-	absHat0X = 0x10
-	absHat0Y = 0x11
+	relWheelHiRes  = 0x0b
+	relHWheelHiRes = 0x0c
+
+	absX        = 0x00
+	absY        = 0x01
+	absZ        = 0x02
+	absRX       = 0x03
+	absRY       = 0x04
+	absRZ       = 0x05
+	absHat0X    = 0x10
+	absHat0Y    = 0x11
+	absPressure = 0x18
 
 	absMtSlot       = 0x2f
 	absMtTouchMajor = 0x30
 	absMtPositionX  = 0x35
 	absMtPositionY  = 0x36
 	absMtTrackingId = 0x39
+	absMtPressure   = 0x3a
 
 	synReport        = 0
 	evMouseBtnLeft   = 0x110
@@ -55,12 +82,19 @@ const (
 	evBtnTouch       = 0x14a
 
 	mscScan = 0x04
+
+	ffGain       = 0x60
+	ffAutocenter = 0x61
 )
 
 const (
 	btnStateReleased = 0
 	btnStatePressed  = 1
 	absSize          = 64
+	propMax          = 0x1f
+
+	// UI_DEV_SETUP and UI_ABS_SETUP are available as of this version of the uinput protocol (linux 4.5)
+	uinputVersionDevSetup = 5
 )
 
 type inputID struct {
@@ -81,6 +115,30 @@ type uinputUserDev struct {
 	Absflat    [absSize]int32
 }
 
+// translated to go from uinput.h
+type uinputSetup struct {
+	ID         inputID
+	Name       [uinputMaxNameSize]byte
+	EffectsMax uint32
+}
+
+// translated to go from input.h
+type absInfo struct {
+	Value      int32
+	Minimum    int32
+	Maximum    int32
+	Fuzz       int32
+	Flat       int32
+	Resolution int32
+}
+
+// translated to go from uinput.h
+type uinputAbsSetup struct {
+	Code    uint16
+	_       uint16
+	Absinfo absInfo
+}
+
 // translated to go from input.h
 type inputEvent struct {
 	Time  syscall.Timeval
@@ -88,3 +146,39 @@ type inputEvent struct {
 	Code  uint16
 	Value int32
 }
+
+// translated to go from input.h (the union is kept as raw bytes and decoded on demand)
+type ffEffect struct {
+	Type      uint16
+	ID        int16
+	Direction uint16
+	Trigger   ffTrigger
+	Replay    ffReplay
+	_         uint16
+	U         [32]byte
+}
+
+type ffTrigger struct {
+	Button   uint16
+	Interval uint16
+}
+
+type ffReplay struct {
+	Length uint16
+	Delay  uint16
+}
+
+// translated to go from uinput.h
+type uinputFfUpload struct {
+	RequestID uint32
+	Retval    int32
+	Effect    ffEffect
+	Old       ffEffect
+}
+
+// translated to go from uinput.h
+type uinputFfErase struct {
+	RequestID uint32
+	Retval    int32
+	EffectID  uint32
+}
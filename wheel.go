@@ -0,0 +1,149 @@
+package uinput
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// WheelDetent is the number of high-resolution wheel units that make up a single detent (notch) of a wheel.
+const WheelDetent = 120
+
+// wheelState accumulates high-resolution wheel movements until a whole detent is reached.
+type wheelState struct {
+	mutex     sync.Mutex
+	remainder [2]int32
+}
+
+func wheelCodes(horizontal bool) (legacy uint16, hiRes uint16) {
+	if horizontal {
+		return relHWheel, relHWheelHiRes
+	}
+	return relWheel, relWheelHiRes
+}
+
+// sendWheelEvent will send a wheel movement by whole detents, along with the matching high-resolution movement.
+func sendWheelEvent(deviceFile uinputFile, horizontal bool, delta int32) error {
+	legacy, hiRes := wheelCodes(horizontal)
+	frame := newFrame(deviceFile)
+	frame.Emit(evRel, legacy, delta)
+	frame.Emit(evRel, hiRes, delta*WheelDetent)
+	err := frame.Flush()
+	if err != nil {
+		return fmt.Errorf("failed to send wheel event: %v", err)
+	}
+	return nil
+}
+
+// scroll will send a high-resolution wheel movement. A legacy wheel event is added whenever the accumulated movement
+// reaches a whole detent. Changing the direction discards the accumulated movement.
+func (w *wheelState) scroll(deviceFile uinputFile, horizontal bool, delta int32) error {
+	if delta == 0 {
+		return nil
+	}
+
+	axis := 0
+	if horizontal {
+		axis = 1
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	remainder := w.remainder[axis]
+	if remainder != 0 && (remainder < 0) != (delta < 0) {
+		remainder = 0
+	}
+	remainder += delta
+	detents := remainder / WheelDetent
+	remainder -= detents * WheelDetent
+
+	legacy, hiRes := wheelCodes(horizontal)
+	frame := newFrame(deviceFile)
+	if detents != 0 {
+		frame.Emit(evRel, legacy, detents)
+	}
+	frame.Emit(evRel, hiRes, delta)
+	err := frame.Flush()
+	if err != nil {
+		return fmt.Errorf("failed to send high-resolution wheel event: %v", err)
+	}
+	w.remainder[axis] = remainder
+	return nil
+}
+
+// A HiResScroller is a device that supports smooth scrolling (like Mouse and MouseAbs).
+type HiResScroller interface {
+	WheelHiRes(horizontal bool, delta int32) error
+}
+
+// interval at which kinetic scrolling sends wheel events
+const kineticScrollInterval = 10 * time.Millisecond
+
+// minimum velocity (in high-resolution units per second) of a kinetic scroll, below which scrolling stops
+const kineticScrollMinVelocity = WheelDetent
+
+// A KineticScroll spreads a fling over time, just like touchpads do after the fingers were lifted.
+type KineticScroll struct {
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+	err  error
+}
+
+// StartKineticScroll will start scrolling with the given velocity (in high-resolution units, i.e. 1/120 of a detent,
+// per second). The velocity decreases by the given friction, which is the fraction of the velocity that is lost per
+// second (e.g. 0.95), until it falls below one detent per second. Scroll events are sent from a separate goroutine.
+func StartKineticScroll(scroller HiResScroller, horizontal bool, velocity float64, friction float64) (*KineticScroll, error) {
+	if friction <= 0 || friction >= 1 {
+		return nil, fmt.Errorf("friction must be between 0 and 1 (exclusive), but is %v", friction)
+	}
+
+	k := &KineticScroll{stop: make(chan struct{}), done: make(chan struct{})}
+	go k.run(scroller, horizontal, velocity, friction)
+	return k, nil
+}
+
+func (k *KineticScroll) run(scroller HiResScroller, horizontal bool, velocity float64, friction float64) {
+	defer close(k.done)
+
+	ticker := time.NewTicker(kineticScrollInterval)
+	defer ticker.Stop()
+
+	decay := math.Pow(1-friction, kineticScrollInterval.Seconds())
+	var position float64
+	var sent int32
+	for math.Abs(velocity) >= kineticScrollMinVelocity {
+		select {
+		case <-k.stop:
+			return
+		case <-ticker.C:
+		}
+
+		position += velocity * kineticScrollInterval.Seconds()
+		velocity *= decay
+		delta := int32(position) - sent
+		if delta == 0 {
+			continue
+		}
+		err := scroller.WheelHiRes(horizontal, delta)
+		if err != nil {
+			k.err = err
+			return
+		}
+		sent += delta
+	}
+}
+
+// Stop will end the kinetic scroll immediately. It is safe to call Stop multiple times.
+func (k *KineticScroll) Stop() {
+	k.once.Do(func() { close(k.stop) })
+	<-k.done
+}
+
+// Wait will block until the kinetic scroll has ended and return the error that ended it, if any.
+func (k *KineticScroll) Wait() error {
+	<-k.done
+	return k.err
+}
@@ -0,0 +1,123 @@
+package uinput
+
+import "testing"
+
+func TestWheelSendsLegacyAndHiResEvents(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	mouse, err := CreateMouse(fake.Path(), []byte("Test Mouse"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual mouse. Last error was: %s\n", err)
+	}
+	defer mouse.Close()
+	dev := fake.Device("Test Mouse")
+	dev.ExpectCodes(t, EvRel, RelX, RelY, RelHWheel, RelWheel, RelWheelHiRes, RelHWheelHiRes)
+
+	err = mouse.Wheel(true, -2)
+	if err != nil {
+		t.Fatalf("Failed to send wheel event. Last error was: %s\n", err)
+	}
+	dev.ExpectEvents(t,
+		Event{Type: EvRel, Code: RelHWheel, Value: -2},
+		Event{Type: EvRel, Code: RelHWheelHiRes, Value: -240},
+		Event{Type: EvSyn, Code: SynReport})
+}
+
+func TestWheelHiResAccumulatesDetents(t *testing.T) {
+	fake := NewFake()
+	defer fake.Close()
+
+	mouse, err := CreateMouseAbs(fake.Path(), []byte("Test Mouse"), 0, 1024, 0, 768)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual mouse. Last error was: %s\n", err)
+	}
+	defer mouse.Close()
+	dev := fake.Device("Test Mouse")
+
+	for _, delta := range []int32{50, 50, 50, 150, -30} {
+		err = mouse.WheelHiRes(false, delta)
+		if err != nil {
+			t.Fatalf("Failed to send wheel event. Last error was: %s\n", err)
+		}
+	}
+	dev.ExpectEvents(t,
+		Event{Type: EvRel, Code: RelWheelHiRes, Value: 50},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvRel, Code: RelWheelHiRes, Value: 50},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvRel, Code: RelWheel, Value: 1},
+		Event{Type: EvRel, Code: RelWheelHiRes, Value: 50},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvRel, Code: RelWheel, Value: 1},
+		Event{Type: EvRel, Code: RelWheelHiRes, Value: 150},
+		Event{Type: EvSyn, Code: SynReport},
+		Event{Type: EvRel, Code: RelWheelHiRes, Value: -30},
+		Event{Type: EvSyn, Code: SynReport})
+
+	// the direction changed, so -30 - 90 makes up a single detent
+	err = mouse.WheelHiRes(false, -90)
+	if err != nil {
+		t.Fatalf("Failed to send wheel event. Last error was: %s\n", err)
+	}
+	dev.ExpectEvents(t,
+		Event{Type: EvRel, Code: RelWheel, Value: -1},
+		Event{Type: EvRel, Code: RelWheelHiRes, Value: -90},
+		Event{Type: EvSyn, Code: SynReport})
+}
+
+type recordingScroller struct {
+	deltas []int32
+}
+
+func (r *recordingScroller) WheelHiRes(horizontal bool, delta int32) error {
+	r.deltas = append(r.deltas, delta)
+	return nil
+}
+
+func TestKineticScrollDecays(t *testing.T) {
+	scroller := &recordingScroller{}
+	k, err := StartKineticScroll(scroller, false, 2400, 0.99)
+	if err != nil {
+		t.Fatalf("Failed to start kinetic scroll. Last error was: %s\n", err)
+	}
+	err = k.Wait()
+	if err != nil {
+		t.Fatalf("Kinetic scroll failed. Last error was: %s\n", err)
+	}
+
+	if len(scroller.deltas) < 2 {
+		t.Fatalf("Expected multiple scroll events, but got %v", scroller.deltas)
+	}
+	if scroller.deltas[0] < scroller.deltas[len(scroller.deltas)-1] {
+		t.Fatalf("Expected scrolling to slow down, but got %v", scroller.deltas)
+	}
+	var total int32
+	for _, delta := range scroller.deltas {
+		total += delta
+	}
+	// the fling covers roughly (v - 120) / -ln(1 - friction), which is about 495 units
+	if total < 440 || total > 550 {
+		t.Fatalf("Expected a total of about 495 units, but got %d", total)
+	}
+}
+
+func TestKineticScrollCanBeStopped(t *testing.T) {
+	scroller := &recordingScroller{}
+	k, err := StartKineticScroll(scroller, true, -1200, 0.01)
+	if err != nil {
+		t.Fatalf("Failed to start kinetic scroll. Last error was: %s\n", err)
+	}
+	k.Stop()
+	k.Stop()
+	if k.Wait() != nil {
+		t.Fatalf("Expected no error after stopping")
+	}
+}
+
+func TestKineticScrollRejectsInvalidFriction(t *testing.T) {
+	_, err := StartKineticScroll(&recordingScroller{}, false, 1200, 1)
+	if err == nil {
+		t.Fatalf("Expected an error, but got none")
+	}
+}